@@ -0,0 +1,261 @@
+package minecraft
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// minRefreshTimeout is the least time background refreshes are given when the
+// Client has no Timeout
+var minRefreshTimeout = 10 * time.Second
+
+// ResponseCache keeps the API responses (profiles, session profiles and
+// textures) so they can be reused until their TTL. With StaleWhileRevalidate,
+// responses past their TTL are served (flagged as Stale) while being
+// refreshed in the background, and whenever fetching a fresh one fails.
+type ResponseCache struct {
+	// TTL is how long responses are fresh for
+	TTL time.Duration
+	// StaleWhileRevalidate enables serving stale responses
+	StaleWhileRevalidate bool
+	// MaxStale, if set, is how long past their TTL responses are served
+	// while being refreshed. Past it they are fetched (but still served if
+	// that fails).
+	MaxStale time.Duration
+	// MaxEntries, if set, is the most responses kept (the least recently
+	// used are dropped)
+	MaxEntries int
+
+	mu         sync.Mutex
+	entries    map[string]*list.Element
+	order      *list.List
+	refreshing map[string]bool
+	wg         sync.WaitGroup
+}
+
+// cacheEntry is a cached response
+type cacheEntry struct {
+	key       string
+	body      []byte
+	header    http.Header
+	finalURL  string
+	fetchedAt time.Time
+}
+
+// NewResponseCache returns a ResponseCache keeping responses for ttl
+func NewResponseCache(ttl time.Duration) *ResponseCache {
+	return &ResponseCache{TTL: ttl}
+}
+
+func (c *ResponseCache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*cacheEntry), true
+}
+
+func (c *ResponseCache) set(entry *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.entries == nil {
+		c.entries = make(map[string]*list.Element)
+		c.order = list.New()
+	}
+	if element, ok := c.entries[entry.key]; ok {
+		element.Value = entry
+		c.order.MoveToFront(element)
+		return
+	}
+	c.entries[entry.key] = c.order.PushFront(entry)
+
+	if c.MaxEntries > 0 && c.order.Len() > c.MaxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// Len returns the number of responses cached
+func (c *ResponseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
+
+// Wait waits for any background refreshes to finish
+func (c *ResponseCache) Wait() {
+	c.wg.Wait()
+}
+
+// startRefresh reports whether a background refresh of the key should be
+// started (ie. there isn't one already)
+func (c *ResponseCache) startRefresh(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.refreshing[key] {
+		return false
+	}
+	if c.refreshing == nil {
+		c.refreshing = make(map[string]bool)
+	}
+	c.refreshing[key] = true
+	c.wg.Add(1)
+	return true
+}
+
+func (c *ResponseCache) endRefresh(key string) {
+	c.mu.Lock()
+	delete(c.refreshing, key)
+	c.mu.Unlock()
+	c.wg.Done()
+}
+
+// cachedResponse is apiResponse using the Cache
+func (mc *Minecraft) cachedResponse(ctx context.Context, kind string, url string) (*http.Response, error) {
+	c := mc.Cache
+	entry, ok := c.get(url)
+	if ok {
+		age := time.Since(entry.fetchedAt)
+		if age < c.TTL {
+			return mc.cacheHit(kind, entry, false), nil
+		}
+		if c.StaleWhileRevalidate && (c.MaxStale == 0 || age < c.TTL+c.MaxStale) {
+			mc.refresh(kind, url)
+			return mc.cacheHit(kind, entry, true), nil
+		}
+	}
+
+	resp, err := mc.fetchAndCache(ctx, kind, url)
+	if err != nil && ok && c.StaleWhileRevalidate && errors.Cause(err) != ErrUserNotFound {
+		// The last known response is better than nothing
+		if resp != nil {
+			resp.Body.Close()
+		}
+		return mc.cacheHit(kind, entry, true), nil
+	}
+	return resp, err
+}
+
+// refresh fetches the response in the background, unless that is already
+// happening
+func (mc *Minecraft) refresh(kind string, url string) {
+	c := mc.Cache
+	if !c.startRefresh(url) {
+		return
+	}
+
+	// Without a deadline a hung request would stop the key ever being
+	// refreshed again
+	timeout := c.TTL
+	if mc.Client != nil && mc.Client.Timeout > 0 {
+		timeout = mc.Client.Timeout
+	} else if timeout < minRefreshTimeout {
+		timeout = minRefreshTimeout
+	}
+
+	go func() {
+		defer c.endRefresh(url)
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		resp, err := mc.fetchAndCache(ctx, kind, url)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		if err != nil && mc.Logger != nil {
+			mc.Logger.Warn("minecraft.refresh", "url", url, "error", err.Error())
+		}
+	}()
+}
+
+// fetchAndCache fetches the response, caching it if it was successful
+func (mc *Minecraft) fetchAndCache(ctx context.Context, kind string, url string) (*http.Response, error) {
+	resp, err := mc.fetchResponse(ctx, kind, url)
+	if err != nil {
+		return resp, err
+	}
+	defer resp.Body.Close()
+
+	// Textures larger than MaxTextureBytes are read far enough to be
+	// rejected, but not cached
+	body := io.Reader(resp.Body)
+	if kind == KindTexture && mc.MaxTextureBytes > 0 {
+		body = io.LimitReader(resp.Body, mc.MaxTextureBytes+1)
+	}
+	raw, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to read response")
+	}
+
+	entry := &cacheEntry{
+		key:       url,
+		body:      raw,
+		header:    resp.Header,
+		finalURL:  resp.Request.URL.String(),
+		fetchedAt: time.Now(),
+	}
+	if kind != KindTexture || mc.MaxTextureBytes <= 0 || int64(len(raw)) <= mc.MaxTextureBytes {
+		mc.Cache.set(entry)
+	}
+	return entry.response(false), nil
+}
+
+// cacheHit returns the cached response, telling the Observer
+func (mc *Minecraft) cacheHit(kind string, entry *cacheEntry, stale bool) *http.Response {
+	if mc.Observer != nil {
+		mc.Observer.ObserveRequest(RequestEvent{
+			Kind:     kind,
+			URL:      entry.key,
+			Status:   http.StatusOK,
+			Bytes:    int64(len(entry.body)),
+			CacheHit: true,
+		})
+	}
+	return entry.response(stale)
+}
+
+// cachedBody is the body of a response made from a cacheEntry
+type cachedBody struct {
+	io.ReadCloser
+	stale bool
+}
+
+// response makes a response of the entry, flagged as stale if it is
+func (e *cacheEntry) response(stale bool) *http.Response {
+	header := e.header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	finalURL, _ := url.Parse(e.finalURL)
+
+	return &http.Response{
+		Status:        strconv.Itoa(http.StatusOK) + " " + http.StatusText(http.StatusOK),
+		StatusCode:    http.StatusOK,
+		Header:        header,
+		Body:          &cachedBody{ioutil.NopCloser(bytes.NewReader(e.body)), stale},
+		ContentLength: int64(len(e.body)),
+		Request:       &http.Request{Method: "GET", URL: finalURL},
+	}
+}
+
+// isStale reports whether the response was served stale from the cache
+func isStale(resp *http.Response) bool {
+	body, ok := resp.Body.(*cachedBody)
+	return ok && body.stale
+}
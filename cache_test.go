@@ -0,0 +1,215 @@
+// cache_test.go
+package minecraft
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// outageTransport counts the requests and, when down, rate limits them all
+type outageTransport struct {
+	rt       http.RoundTripper
+	down     int32
+	requests int32
+}
+
+func (t *outageTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	atomic.AddInt32(&t.requests, 1)
+	if atomic.LoadInt32(&t.down) == 1 {
+		return &http.Response{
+			Status:     "429 Too Many Requests",
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{},
+			Body:       ioutil.NopCloser(strings.NewReader("")),
+			Request:    req,
+		}, nil
+	}
+	return t.rt.RoundTrip(req)
+}
+
+// roundTripperFunc allows the use of a function as an http.RoundTripper
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+func TestResponseCache(t *testing.T) {
+
+	Convey("Test ResponseCache", t, func() {
+
+		transport := &outageTransport{rt: mcTest.Client.Transport}
+		mc := *mcTest
+		mc.Client = &http.Client{Transport: transport}
+		mc.Cache = NewResponseCache(time.Hour)
+
+		requests := func() int {
+			return int(atomic.LoadInt32(&transport.requests))
+		}
+		setDown := func(down bool) {
+			if down {
+				atomic.StoreInt32(&transport.down, 1)
+			} else {
+				atomic.StoreInt32(&transport.down, 0)
+			}
+		}
+
+		profile, err := mc.FetchProfile("citricsquid")
+		So(err, ShouldBeNil)
+		So(profile.Stale, ShouldBeFalse)
+		So(requests(), ShouldEqual, 4)
+		So(mc.Cache.Len(), ShouldEqual, 4)
+
+		Convey("Fresh responses should be reused", func() {
+			var hits int
+			mc.Observer = ObserverFunc(func(event RequestEvent) {
+				if event.CacheHit {
+					hits++
+				}
+			})
+
+			cached, err := mc.FetchProfile("citricsquid")
+			So(err, ShouldBeNil)
+			So(requests(), ShouldEqual, 4)
+			So(hits, ShouldEqual, 4)
+			So(cached.Stale, ShouldBeFalse)
+			So(cached.Skin.Hash, ShouldEqual, profile.Skin.Hash)
+			So(cached.Skin.Raw, ShouldResemble, profile.Skin.Raw)
+		})
+
+		Convey("Without StaleWhileRevalidate expired responses should be fetched", func() {
+			mc.Cache.TTL = time.Nanosecond
+			setDown(true)
+
+			_, err := mc.FetchProfile("citricsquid")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to FetchProfile: unable to GetAPIProfile: rate limited")
+		})
+
+		Convey("With StaleWhileRevalidate", func() {
+			mc.Cache.TTL = time.Nanosecond
+			mc.Cache.StaleWhileRevalidate = true
+
+			Convey("Expired responses should be served stale and refreshed", func() {
+				stale, err := mc.FetchProfile("citricsquid")
+				So(err, ShouldBeNil)
+				So(stale.Stale, ShouldBeTrue)
+				So(stale.Skin.Stale, ShouldBeTrue)
+				So(stale.Skin.Header.Get("Warning"), ShouldBeEmpty)
+				So(stale.Skin.Hash, ShouldEqual, profile.Skin.Hash)
+
+				mc.Cache.Wait()
+				So(requests(), ShouldEqual, 8)
+
+				// The refreshed responses are fresh
+				mc.Cache.TTL = time.Hour
+				fresh, err := mc.FetchProfile("citricsquid")
+				So(err, ShouldBeNil)
+				So(fresh.Stale, ShouldBeFalse)
+				So(requests(), ShouldEqual, 8)
+			})
+
+			Convey("Expired responses should be served during an outage", func() {
+				setDown(true)
+
+				skin, err := mc.FetchSkinUUID("48a0a7e4d5594873a617dc189f76a8a1")
+				So(err, ShouldBeNil)
+				So(skin.Stale, ShouldBeTrue)
+				So(skin.Hash, ShouldEqual, profile.Skin.Hash)
+
+				// The failed refresh keeps the last known response
+				mc.Cache.Wait()
+				So(mc.Cache.Len(), ShouldEqual, 4)
+			})
+
+			Convey("Responses past MaxStale should be served when fetching fails", func() {
+				mc.Cache.MaxStale = time.Nanosecond
+				setDown(true)
+
+				stale, err := mc.FetchProfile("citricsquid")
+				So(err, ShouldBeNil)
+				So(stale.Stale, ShouldBeTrue)
+				So(stale.Skin.Stale, ShouldBeTrue)
+				So(requests(), ShouldEqual, 8)
+			})
+
+			Convey("Hung refreshes should time out", func() {
+				defer func(timeout time.Duration) { minRefreshTimeout = timeout }(minRefreshTimeout)
+				minRefreshTimeout = 50 * time.Millisecond
+				hang := make(chan struct{})
+				defer close(hang)
+				mc.Client = &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+					select {
+					case <-req.Context().Done():
+						return nil, req.Context().Err()
+					case <-hang:
+						return nil, errors.New("hung up")
+					}
+				})}
+
+				_, err := mc.GetAPIProfile("citricsquid")
+				So(err, ShouldBeNil)
+
+				done := make(chan struct{})
+				go func() {
+					mc.Cache.Wait()
+					close(done)
+				}()
+				select {
+				case <-done:
+				case <-time.After(5 * time.Second):
+					t.Error("the refresh did not time out")
+				}
+			})
+
+			Convey("Stale username lookups should be flagged", func() {
+				mc.Cache = &ResponseCache{TTL: time.Hour, StaleWhileRevalidate: true}
+				mc.UsernameAPI.SkinURL = ""
+				_, err := mc.GetAPIProfile("citricsquid")
+				So(err, ShouldBeNil)
+				mc.Cache.TTL = time.Nanosecond
+
+				stale, err := mc.FetchProfile("citricsquid")
+				So(err, ShouldBeNil)
+				So(stale.Stale, ShouldBeTrue)
+				So(stale.Skin.Stale, ShouldBeFalse)
+
+				skin, err := mc.FetchSkinPlayer("citricsquid")
+				So(err, ShouldBeNil)
+				So(skin.Stale, ShouldBeTrue)
+				mc.Cache.Wait()
+			})
+
+			Convey("Uncached responses should still fail", func() {
+				setDown(true)
+
+				_, err := mc.FetchProfile("clone1018")
+				So(err, ShouldNotBeNil)
+			})
+
+		})
+
+		Convey("MaxEntries should drop the least recently used", func() {
+			mc.Cache.MaxEntries = 4
+			mc.FetchProfile("citricsquid")
+
+			_, err := mc.GetAPIProfile("clone1018")
+			So(err, ShouldBeNil)
+			So(mc.Cache.Len(), ShouldEqual, 4)
+
+			// The citricsquid profile lookup was used least recently
+			requested := requests()
+			mc.GetAPIProfile("citricsquid")
+			So(requests(), ShouldEqual, requested+1)
+		})
+
+	})
+
+}
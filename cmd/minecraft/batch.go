@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minotar/minecraft"
+	"github.com/pkg/errors"
+)
+
+// batchResult is the JSON line written for each player
+type batchResult struct {
+	Player   string `json:"player"`
+	Status   string `json:"status"`
+	UUID     string `json:"uuid,omitempty"`
+	Username string `json:"username,omitempty"`
+	// Skin and Cape are the files the textures were written to
+	Skin string `json:"skin,omitempty"`
+	Cape string `json:"cape,omitempty"`
+	// Error is one of "invalid", "not_found", "rate_limited" or "error"
+	Error   string `json:"error,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+// batchJob is a player waiting to be resolved, with where to send the result
+type batchJob struct {
+	player string
+	result chan batchResult
+}
+
+// classify picks the error class for the JSON line
+func classify(err error) string {
+	switch errors.Cause(err) {
+	case minecraft.ErrUserNotFound:
+		return "not_found"
+	case minecraft.ErrRateLimited:
+		return "rate_limited"
+	}
+	return "error"
+}
+
+// batch resolves the players listed (one per line) in a file or stdin,
+// writing a JSON line for each in the order they were listed
+func (c *command) batch(args []string) error {
+	workers := c.flags.Int("workers", 4, "number of players to resolve at once")
+	rate := c.flags.Float64("rate", 5, "most players to start resolving per second (0 for no limit)")
+	dir := c.flags.String("dir", "", "directory to download skins and capes to")
+	if err := c.flags.Parse(args); err != nil {
+		return errUsage
+	}
+	if c.flags.NArg() > 1 {
+		fmt.Fprintf(c.stderr, "minecraft %s: expected at most one file\n", c.name)
+		return errUsage
+	}
+	if *workers < 1 {
+		return errors.Errorf("workers must be at least 1 (got %d)", *workers)
+	}
+	if *rate < 0 {
+		return errors.Errorf("rate must not be negative (got %v)", *rate)
+	}
+
+	in := c.stdin
+	if file := c.flags.Arg(0); file != "" && file != "-" {
+		f, err := os.Open(file)
+		if err != nil {
+			return errors.Wrap(err, "unable to open input")
+		}
+		defer f.Close()
+		in = f
+	}
+	if *dir != "" {
+		if err := os.MkdirAll(*dir, 0755); err != nil {
+			return errors.Wrap(err, "unable to create directory")
+		}
+	}
+
+	var tick <-chan time.Time
+	if *rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / *rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	jobs := make(chan batchJob)
+	var wg sync.WaitGroup
+	for i := 0; i < *workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if tick != nil {
+					<-tick
+				}
+				job.result <- c.resolve(job.player, *dir)
+			}
+		}()
+	}
+
+	// Results are queued in the order they were listed, so the writer can
+	// wait on each in turn while the workers carry on
+	pending := make(chan chan batchResult, *workers)
+	done := make(chan error, 1)
+	var total, failed int
+	go func() {
+		encoder := json.NewEncoder(c.stdout)
+		var err error
+		for result := range pending {
+			r := <-result
+			total++
+			if r.Status != "ok" {
+				failed++
+			}
+			if err == nil {
+				err = encoder.Encode(r)
+			}
+		}
+		done <- err
+	}()
+
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		player := strings.TrimSpace(scanner.Text())
+		if player == "" || strings.HasPrefix(player, "#") {
+			continue
+		}
+
+		result := make(chan batchResult, 1)
+		pending <- result
+		if !minecraft.RegexUsernameOrUUID.MatchString(player) {
+			result <- batchResult{Player: player, Status: "error", Error: "invalid", Message: "invalid player"}
+			continue
+		}
+		jobs <- batchJob{player, result}
+	}
+	close(jobs)
+	wg.Wait()
+	close(pending)
+
+	if err := <-done; err != nil {
+		return errors.Wrap(err, "unable to write result")
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.Wrap(err, "unable to read input")
+	}
+	fmt.Fprintf(c.stderr, "minecraft %s: resolved %d of %d players\n", c.name, total-failed, total)
+	return nil
+}
+
+// resolve looks up the player, fetching their profile and writing their
+// textures to dir if it is set
+func (c *command) resolve(player string, dir string) batchResult {
+	result := batchResult{Player: player, Status: "error"}
+
+	if dir == "" {
+		// There's nowhere to write the textures, so don't download them
+		user, err := c.lookup(player)
+		if err != nil {
+			result.Error, result.Message = classify(err), err.Error()
+			return result
+		}
+		result.UUID, result.Username = user.UUID, user.Username
+		result.Status = "ok"
+		return result
+	}
+
+	profile, err := c.mc.FetchProfile(player)
+	if err != nil {
+		result.Error, result.Message = classify(err), err.Error()
+		return result
+	}
+	result.UUID, result.Username = profile.UUID, profile.Username
+
+	if profile.Skin != nil {
+		result.Skin = filepath.Join(dir, profile.UUID+".skin.png")
+		err = writeFile(result.Skin, profile.Skin.Texture)
+	}
+	if profile.Cape != nil && err == nil {
+		result.Cape = filepath.Join(dir, profile.UUID+".cape.png")
+		err = writeFile(result.Cape, profile.Cape.Texture)
+	}
+	if err != nil {
+		result.Error, result.Message = "error", err.Error()
+		return result
+	}
+
+	result.Status = "ok"
+	return result
+}
+
+// lookup finds the player's UUID and username without fetching their
+// textures: the API profile has both for a username, while a UUID needs the
+// session profile
+func (c *command) lookup(player string) (minecraft.User, error) {
+	if minecraft.RegexUsername.MatchString(player) {
+		apiProfile, err := c.mc.GetAPIProfile(player)
+		return apiProfile.User, err
+	}
+
+	uuid, err := c.mc.NormalizePlayerForUUID(player)
+	if err != nil {
+		return minecraft.User{}, err
+	}
+	sessionProfile, err := c.mc.GetSessionProfile(uuid)
+	return sessionProfile.User, err
+}
+
+// writeFile writes the texture to the file as it was served
+func writeFile(file string, texture minecraft.Texture) error {
+	f, err := os.Create(file)
+	if err != nil {
+		return errors.Wrap(err, "unable to create texture file")
+	}
+	if _, err := texture.WriteRaw(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,118 @@
+// batch_test.go
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// readResults decodes the JSON lines
+func readResults(output string) []batchResult {
+	var results []batchResult
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		var result batchResult
+		So(json.Unmarshal(scanner.Bytes(), &result), ShouldBeNil)
+		results = append(results, result)
+	}
+	return results
+}
+
+func TestBatch(t *testing.T) {
+
+	Convey("Test batch", t, func() {
+
+		dir, _ := ioutil.TempDir("", "minecraft")
+		defer os.RemoveAll(dir)
+
+		Convey("Players should be resolved in the order they were listed", func() {
+			input := "clone1018\n\n# a comment\n  citricsquid  \n_-proscope-_\nskmkj88200aklk\nRateLimitAPI\n"
+			code, stdout, stderr := runMockInput(input, "batch", "-rate", "0", "-workers", "3", "-dir", dir)
+			So(code, ShouldEqual, 0)
+			So(stderr.String(), ShouldEqual, "minecraft batch: resolved 2 of 5 players\n")
+
+			results := readResults(stdout.String())
+			So(results, ShouldHaveLength, 5)
+
+			So(results[0], ShouldResemble, batchResult{
+				Player:   "clone1018",
+				Status:   "ok",
+				UUID:     "d9135e082f2244c89cb0bee234155292",
+				Username: "clone1018",
+				Skin:     filepath.Join(dir, "d9135e082f2244c89cb0bee234155292.skin.png"),
+			})
+			So(results[1].Player, ShouldEqual, "citricsquid")
+			So(results[1].Status, ShouldEqual, "ok")
+			So(results[1].Cape, ShouldEqual, filepath.Join(dir, "48a0a7e4d5594873a617dc189f76a8a1.cape.png"))
+
+			So(results[2].Error, ShouldEqual, "invalid")
+			So(results[3].Error, ShouldEqual, "not_found")
+			So(results[4].Error, ShouldEqual, "rate_limited")
+			for _, result := range results[2:] {
+				So(result.Status, ShouldEqual, "error")
+				So(result.Message, ShouldNotBeEmpty)
+			}
+
+			files, _ := filepath.Glob(filepath.Join(dir, "*.png"))
+			So(files, ShouldHaveLength, 3)
+		})
+
+		Convey("Players can be read from a file", func() {
+			file := filepath.Join(dir, "players.txt")
+			ioutil.WriteFile(file, []byte("clone1018\n"), 0644)
+
+			code, stdout, _ := runMock("batch", "-rate", "0", file)
+			So(code, ShouldEqual, 0)
+
+			results := readResults(stdout.String())
+			So(results, ShouldHaveLength, 1)
+			So(results[0].UUID, ShouldEqual, "d9135e082f2244c89cb0bee234155292")
+			So(results[0].Skin, ShouldBeEmpty)
+		})
+
+		Convey("Textures should only be downloaded with a directory", func() {
+			// The skin of 404STexture is missing
+			code, stdout, _ := runMockInput("404STexture\n00000000000000000000000000000010\n", "batch", "-rate", "0")
+			So(code, ShouldEqual, 0)
+			results := readResults(stdout.String())
+			So(results, ShouldResemble, []batchResult{
+				{Player: "404STexture", Status: "ok", UUID: "00000000000000000000000000000010", Username: "404STexture"},
+				{Player: "00000000000000000000000000000010", Status: "ok", UUID: "00000000000000000000000000000010", Username: "404STexture"},
+			})
+
+			code, stdout, _ = runMockInput("404STexture\n", "batch", "-rate", "0", "-dir", dir)
+			So(code, ShouldEqual, 0)
+			So(readResults(stdout.String())[0].Status, ShouldEqual, "error")
+		})
+
+		Convey("Players should be rate limited", func() {
+			start := time.Now()
+			code, stdout, _ := runMockInput("clone1018\nclone1018\nclone1018\n", "batch", "-rate", "20", "-workers", "3")
+			So(code, ShouldEqual, 0)
+			So(readResults(stdout.String()), ShouldHaveLength, 3)
+			// The third can't start until 150ms in
+			So(time.Since(start), ShouldBeGreaterThanOrEqualTo, 150*time.Millisecond)
+		})
+
+		Convey("Bad flags should fail", func() {
+			code, _, _ := runMock("batch", "-workers", "0")
+			So(code, ShouldEqual, 1)
+
+			code, _, _ = runMock("batch", "a.txt", "b.txt")
+			So(code, ShouldEqual, 2)
+
+			code, _, _ = runMock("batch", filepath.Join(dir, "missing.txt"))
+			So(code, ShouldEqual, 1)
+		})
+
+	})
+
+}
@@ -0,0 +1,377 @@
+// Command minecraft looks up Minecraft players and downloads or renders their
+// skins and capes.
+//
+// Usage:
+//
+//	minecraft [flags] <command> [command flags] <player>
+//
+// The commands are:
+//
+//	uuid     print the UUID of a username
+//	profile  print the profile of a player as JSON
+//	skin     download the skin of a player
+//	cape     download the cape of a player
+//	avatar   render the head of a player
+//	body     render the body of a player
+//	batch    resolve players listed in a file, one JSON line each
+//
+// Images are written to stdout unless -o is given. The client is configured
+// with -config (see minecraft.LoadConfig), which the flags override. The
+// endpoint flags (and -proxy, for texture URLs) allow using an alternative or
+// mock API.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+
+	"github.com/minotar/minecraft"
+	"github.com/minotar/minecraft/handler"
+	"github.com/pkg/errors"
+)
+
+const usage = `Usage: minecraft [flags] <command> [command flags] <player>
+
+Commands:
+  uuid <username>              print the UUID of a username
+  profile <player>             print the profile of a player as JSON
+  skin <player> [-o file]      download the skin of a player
+  cape <player> [-o file]      download the cape of a player
+  avatar <player> [-o file]    render the head of a player
+  body <player> [-o file]      render the body of a player
+  batch [file]                 resolve the players listed in a file (or stdin),
+                               writing a JSON line for each (and with -dir,
+                               downloading their textures)
+
+Flags:
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command line, returning the exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	global := flag.NewFlagSet("minecraft", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	global.Usage = func() {
+		fmt.Fprint(stderr, usage)
+		global.PrintDefaults()
+	}
+	configFile := global.String("config", "", "JSON config file (MINECRAFT_* environment variables override it)")
+	preset := global.String("preset", "", "skin server to use (mojang, elyby or littleskin)")
+	profileURL := global.String("profile-url", "", "URL to look up UUIDs from, with the username appended")
+	sessionURL := global.String("session-url", "", "URL to look up session profiles from, with the UUID appended")
+	skinURL := global.String("skin-url", "", "URL to fetch skins by username from, with \"username.png\" appended")
+	capeURL := global.String("cape-url", "", "URL to fetch capes by username from, with \"username.png\" appended")
+	userAgent := global.String("user-agent", "", "User-Agent for requests")
+	timeout := global.Duration("timeout", 0, "timeout for each request (10s by default)")
+	proxy := global.String("proxy", "", "HTTP proxy for every request (eg. a mock server)")
+
+	if err := global.Parse(args); err != nil {
+		return 2
+	}
+	if global.NArg() == 0 {
+		global.Usage()
+		return 2
+	}
+
+	config, err := minecraft.LoadConfig(*configFile)
+	if err != nil {
+		fmt.Fprintf(stderr, "minecraft: %v\n", err)
+		return 1
+	}
+	overrides := []struct {
+		flag  string
+		field *string
+	}{
+		{*preset, &config.Preset},
+		{*profileURL, &config.ProfileURL},
+		{*sessionURL, &config.SessionServerURL},
+		{*skinURL, &config.SkinURL},
+		{*capeURL, &config.CapeURL},
+		{*userAgent, &config.UserAgent},
+	}
+	for _, override := range overrides {
+		if override.flag != "" {
+			*override.field = override.flag
+		}
+	}
+	if *timeout != 0 {
+		config.Timeouts.Request = minecraft.Duration(*timeout)
+	}
+
+	mc, err := config.NewMinecraft()
+	if err != nil {
+		fmt.Fprintf(stderr, "minecraft: %v\n", err)
+		return 1
+	}
+	if *proxy != "" {
+		proxyURL, err := url.Parse(*proxy)
+		if err != nil {
+			fmt.Fprintf(stderr, "minecraft: invalid -proxy: %v\n", err)
+			return 2
+		}
+		mc.Client.Transport.(*http.Transport).Proxy = http.ProxyURL(proxyURL)
+	}
+
+	name, args := global.Arg(0), global.Args()[1:]
+	cmd := &command{name: name, mc: mc, stdin: stdin, stdout: stdout, stderr: stderr}
+	cmd.flags = flag.NewFlagSet(name, flag.ContinueOnError)
+	cmd.flags.SetOutput(stderr)
+
+	switch name {
+	case "uuid":
+		err = cmd.uuid(args)
+	case "profile":
+		err = cmd.profile(args)
+	case "skin":
+		err = cmd.skin(args)
+	case "cape":
+		err = cmd.cape(args)
+	case "avatar":
+		err = cmd.avatar(args)
+	case "body":
+		err = cmd.body(args)
+	case "batch":
+		err = cmd.batch(args)
+	default:
+		fmt.Fprintf(stderr, "minecraft: unknown command %q\n", name)
+		global.Usage()
+		return 2
+	}
+
+	if err == errUsage {
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "minecraft: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// errUsage is returned when the command line is wrong (the flag package will
+// have already explained)
+var errUsage = errors.New("usage")
+
+// command is a command being run
+type command struct {
+	name   string
+	mc     *minecraft.Minecraft
+	flags  *flag.FlagSet
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	output string
+}
+
+// parse parses the flags (which may come before or after the player) and
+// returns the player
+func (c *command) parse(args []string, withOutput bool) (string, error) {
+	if withOutput {
+		c.flags.StringVar(&c.output, "o", "-", "file to write to (- for stdout)")
+	}
+
+	var positional []string
+	for {
+		if err := c.flags.Parse(args); err != nil {
+			return "", errUsage
+		}
+		if c.flags.NArg() == 0 {
+			break
+		}
+		positional = append(positional, c.flags.Arg(0))
+		args = c.flags.Args()[1:]
+	}
+
+	if len(positional) != 1 {
+		fmt.Fprintf(c.stderr, "minecraft %s: expected one player\n", c.name)
+		return "", errUsage
+	}
+	if !minecraft.RegexUsernameOrUUID.MatchString(positional[0]) {
+		return "", errors.Errorf("invalid player %q", positional[0])
+	}
+	return positional[0], nil
+}
+
+// create opens the output for writing
+func (c *command) create() (io.WriteCloser, error) {
+	if c.output == "-" {
+		return nopCloser{c.stdout}, nil
+	}
+	f, err := os.Create(c.output)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create output")
+	}
+	return f, nil
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// writeTexture writes the texture as it was served, or re-encoded when we
+// don't have the original
+func (c *command) writeTexture(texture minecraft.Texture) error {
+	if texture.Raw == nil {
+		return c.writePNG(texture.Image)
+	}
+
+	w, err := c.create()
+	if err != nil {
+		return err
+	}
+	if _, err := texture.WriteRaw(w); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// writePNG writes the image as a PNG
+func (c *command) writePNG(img image.Image) error {
+	w, err := c.create()
+	if err != nil {
+		return err
+	}
+	if err := minecraft.EncodePNG(w, img, minecraft.PNGOptions{OptimizePalette: true}); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (c *command) uuid(args []string) error {
+	dashed := c.flags.Bool("dashed", false, "print the UUID with dashes")
+	player, err := c.parse(args, false)
+	if err != nil {
+		return err
+	}
+
+	uuid, err := c.mc.NormalizePlayerForUUID(player)
+	if err != nil {
+		return err
+	}
+	if *dashed {
+		if uuid, err = minecraft.DashUUID(uuid); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(c.stdout, uuid)
+	return nil
+}
+
+func (c *command) profile(args []string) error {
+	player, err := c.parse(args, false)
+	if err != nil {
+		return err
+	}
+
+	profile, err := c.mc.FetchProfile(player)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(c.stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(handler.NewProfileResponse(profile))
+}
+
+func (c *command) skin(args []string) error {
+	player, err := c.parse(args, true)
+	if err != nil {
+		return err
+	}
+
+	skin, err := c.mc.FetchSkinPlayer(player)
+	if err != nil {
+		return err
+	}
+	return c.writeTexture(skin.Texture)
+}
+
+func (c *command) cape(args []string) error {
+	player, err := c.parse(args, true)
+	if err != nil {
+		return err
+	}
+
+	cape, err := c.mc.FetchCapePlayer(player)
+	if err != nil {
+		return err
+	}
+	return c.writeTexture(cape.Texture)
+}
+
+// renderFlags adds the flags shared by avatar and body
+func (c *command) renderFlags(defaultSize int) (*int, *bool, *bool) {
+	size := c.flags.Int("size", defaultSize, "height of the image in pixels")
+	overlay := c.flags.Bool("overlay", true, "draw the overlays (hat, jacket etc.)")
+	iso := c.flags.Bool("iso", false, "render isometrically rather than flat")
+	return size, overlay, iso
+}
+
+func (c *command) avatar(args []string) error {
+	size, overlay, iso := c.renderFlags(64)
+	player, err := c.parse(args, true)
+	if err != nil {
+		return err
+	}
+	if *size < 1 {
+		return errors.Errorf("size must be at least 1 (got %d)", *size)
+	}
+
+	skin, err := c.mc.FetchSkinPlayer(player)
+	if err != nil {
+		return err
+	}
+
+	var img *image.NRGBA
+	if *iso {
+		img, err = skin.RenderHeadIso(*size, *overlay)
+	} else if img, err = skin.RenderHead(1, *overlay); err == nil {
+		img = minecraft.ResizeNearest(img, *size, *size)
+	}
+	if err != nil {
+		return err
+	}
+	return c.writePNG(img)
+}
+
+func (c *command) body(args []string) error {
+	size, overlay, iso := c.renderFlags(128)
+	player, err := c.parse(args, true)
+	if err != nil {
+		return err
+	}
+	if *size < 2 {
+		return errors.Errorf("size must be at least 2 (got %d)", *size)
+	}
+
+	skin, err := c.mc.FetchSkinPlayer(player)
+	if err != nil {
+		return err
+	}
+
+	var img *image.NRGBA
+	if *iso {
+		img, err = skin.RenderBodyIso(minecraft.IsoOptions{Size: *size, Overlay: *overlay})
+	} else if img, err = skin.RenderBody(1, *overlay); err == nil {
+		img = minecraft.ResizeNearest(img, *size/2, *size)
+	}
+	if err != nil {
+		return err
+	}
+	return c.writePNG(img)
+}
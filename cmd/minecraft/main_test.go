@@ -0,0 +1,197 @@
+// main_test.go
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/minotar/minecraft/handler"
+	"github.com/minotar/minecraft/mockminecraft"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestMain(m *testing.M) {
+	_, shutdown := mockminecraft.Setup(mockminecraft.ReturnMux())
+
+	code := m.Run()
+	shutdown()
+	os.Exit(code)
+}
+
+// runMock runs the command line against the mock server, returning the exit
+// code, stdout and stderr
+func runMock(args ...string) (int, *bytes.Buffer, *bytes.Buffer) {
+	return runMockInput("", args...)
+}
+
+// runMockInput is runMock with the given stdin
+func runMockInput(stdin string, args ...string) (int, *bytes.Buffer, *bytes.Buffer) {
+	args = append([]string{
+		"-profile-url", mockminecraft.TestURL + "/users/profiles/minecraft/",
+		"-session-url", mockminecraft.TestURL + "/session/minecraft/profile/",
+		"-proxy", mockminecraft.TestURL,
+	}, args...)
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	code := run(args, strings.NewReader(stdin), stdout, stderr)
+	return code, stdout, stderr
+}
+
+func TestCommands(t *testing.T) {
+
+	Convey("Test uuid", t, func() {
+
+		Convey("Usernames should be looked up", func() {
+			code, stdout, _ := runMock("uuid", "clone1018")
+
+			So(code, ShouldEqual, 0)
+			So(stdout.String(), ShouldEqual, "d9135e082f2244c89cb0bee234155292\n")
+		})
+
+		Convey("UUIDs can be dashed", func() {
+			code, stdout, _ := runMock("uuid", "-dashed", "clone1018")
+
+			So(code, ShouldEqual, 0)
+			So(stdout.String(), ShouldEqual, "d9135e08-2f22-44c8-9cb0-bee234155292\n")
+		})
+
+		Convey("Missing players should fail", func() {
+			code, _, stderr := runMock("uuid", "skmkj88200aklk")
+
+			So(code, ShouldEqual, 1)
+			So(stderr.String(), ShouldEqual, "minecraft: unable to GetAPIProfile: user not found\n")
+		})
+
+	})
+
+	Convey("Test profile", t, func() {
+
+		Convey("Profiles should be printed as JSON", func() {
+			code, stdout, _ := runMock("profile", "citricsquid")
+			So(code, ShouldEqual, 0)
+
+			var profile handler.ProfileResponse
+			So(json.Unmarshal(stdout.Bytes(), &profile), ShouldBeNil)
+			So(profile.UUIDDashed, ShouldEqual, "48a0a7e4-d559-4873-a617-dc189f76a8a1")
+			So(profile.Skin.Hash, ShouldEqual, "c05454f331fa93b3e38866a9ec52c467")
+			So(profile.Cape.Hash, ShouldEqual, "8cbf8786caba2f05383cf887be592ee6")
+		})
+
+	})
+
+	Convey("Test skin and cape", t, func() {
+
+		Convey("Skins should be written as they were served", func() {
+			code, stdout, _ := runMock("skin", "clone1018")
+
+			So(code, ShouldEqual, 0)
+			So(stdout.Len(), ShouldBeGreaterThan, 0)
+			_, err := png.Decode(stdout)
+			So(err, ShouldBeNil)
+		})
+
+		Convey("Capes should be written to a file", func() {
+			dir, _ := ioutil.TempDir("", "minecraft")
+			defer os.RemoveAll(dir)
+			file := filepath.Join(dir, "cape.png")
+
+			code, stdout, _ := runMock("cape", "citricsquid", "-o", file)
+			So(code, ShouldEqual, 0)
+			So(stdout.Len(), ShouldEqual, 0)
+
+			f, err := os.Open(file)
+			So(err, ShouldBeNil)
+			defer f.Close()
+			img, err := png.Decode(f)
+			So(err, ShouldBeNil)
+			So(img.Bounds().Dx(), ShouldEqual, 64)
+		})
+
+		Convey("The UsernameAPI can be used", func() {
+			code, stdout, _ := runMock("-skin-url", "http://skins.example.net/skins/", "skin", "citricsquid")
+
+			So(code, ShouldEqual, 0)
+			So(stdout.Len(), ShouldBeGreaterThan, 0)
+		})
+
+	})
+
+	Convey("Test avatar and body", t, func() {
+
+		Convey("Avatars should be the size asked for", func() {
+			code, stdout, _ := runMock("avatar", "--size", "32", "clone1018")
+			So(code, ShouldEqual, 0)
+
+			img, err := png.Decode(stdout)
+			So(err, ShouldBeNil)
+			So(img.Bounds().Dx(), ShouldEqual, 32)
+			So(img.Bounds().Dy(), ShouldEqual, 32)
+		})
+
+		Convey("Bodies should be half as wide as high", func() {
+			code, stdout, _ := runMock("body", "clone1018", "-size", "64", "-iso")
+			So(code, ShouldEqual, 0)
+
+			img, err := png.Decode(stdout)
+			So(err, ShouldBeNil)
+			So(img.Bounds().Dx(), ShouldEqual, 32)
+			So(img.Bounds().Dy(), ShouldEqual, 64)
+		})
+
+		Convey("Bad sizes should fail", func() {
+			code, _, stderr := runMock("body", "clone1018", "-size", "1")
+
+			So(code, ShouldEqual, 1)
+			So(stderr.String(), ShouldEqual, "minecraft: size must be at least 2 (got 1)\n")
+		})
+
+	})
+
+	Convey("Test bad command lines", t, func() {
+
+		Convey("No command should show the usage", func() {
+			code, _, stderr := runMock()
+
+			So(code, ShouldEqual, 2)
+			So(stderr.String(), ShouldStartWith, "Usage: minecraft")
+		})
+
+		Convey("Unknown commands should fail", func() {
+			code, _, stderr := runMock("head", "clone1018")
+
+			So(code, ShouldEqual, 2)
+			So(stderr.String(), ShouldStartWith, "minecraft: unknown command \"head\"\n")
+		})
+
+		Convey("Commands need exactly one player", func() {
+			code, _, stderr := runMock("skin")
+			So(code, ShouldEqual, 2)
+			So(stderr.String(), ShouldEqual, "minecraft skin: expected one player\n")
+
+			code, _, _ = runMock("skin", "clone1018", "citricsquid")
+			So(code, ShouldEqual, 2)
+		})
+
+		Convey("Bad configs should fail", func() {
+			code, _, stderr := runMock("-preset", "minotar", "uuid", "clone1018")
+
+			So(code, ShouldEqual, 1)
+			So(stderr.String(), ShouldEqual, "minecraft: unable to NewMinecraft: unknown preset \"minotar\"\n")
+		})
+
+		Convey("Invalid players should fail", func() {
+			code, _, stderr := runMock("skin", "_-proscope-_")
+
+			So(code, ShouldEqual, 1)
+			So(strings.TrimSpace(stderr.String()), ShouldEqual, `minecraft: invalid player "_-proscope-_"`)
+		})
+
+	})
+
+}
@@ -0,0 +1,366 @@
+package minecraft
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Preset is the endpoints of a skin server with a Mojang compatible API
+type Preset struct {
+	UUIDAPI
+	UsernameAPI
+}
+
+// Presets are the known skin servers. The third party ones are as published
+// in their documentation.
+var Presets = map[string]Preset{
+	"mojang": {
+		UUIDAPI: UUIDAPI{
+			SessionServerURL: "https://sessionserver.mojang.com/session/minecraft/profile/",
+			ProfileURL:       "https://api.mojang.com/users/profiles/minecraft/",
+		},
+	},
+	"elyby": {
+		UUIDAPI: UUIDAPI{
+			SessionServerURL: "https://authserver.ely.by/session/profile/",
+			ProfileURL:       "https://authserver.ely.by/api/users/profiles/minecraft/",
+		},
+		UsernameAPI: UsernameAPI{
+			SkinURL: "https://skinsystem.ely.by/skins/",
+			CapeURL: "https://skinsystem.ely.by/cloaks/",
+		},
+	},
+	"littleskin": {
+		UUIDAPI: UUIDAPI{
+			SessionServerURL: "https://littleskin.cn/api/yggdrasil/sessionserver/session/minecraft/profile/",
+			ProfileURL:       "https://littleskin.cn/api/yggdrasil/api/users/profiles/minecraft/",
+		},
+		UsernameAPI: UsernameAPI{
+			SkinURL: "https://littleskin.cn/skin/",
+			CapeURL: "https://littleskin.cn/cape/",
+		},
+	},
+}
+
+// DefaultPreset is the Preset used when none is configured
+const DefaultPreset = "mojang"
+
+// Duration is a time.Duration written in JSON as a string, eg. "10s"
+type Duration time.Duration
+
+// UnmarshalJSON parses the duration with time.ParseDuration
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return errors.Errorf("duration must be a string like \"10s\" (got %s)", b)
+	}
+	parsed, err := time.ParseDuration(s)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// MarshalJSON writes the duration as a string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Config is the configuration of a Minecraft client (and of a handler using
+// it). Anything left empty comes from the Preset, or the defaults.
+type Config struct {
+	// Preset is the name of the skin server in Presets ("mojang" by default)
+	Preset string `json:"preset"`
+
+	ProfileURL       string `json:"profile_url"`
+	SessionServerURL string `json:"session_server_url"`
+	SkinURL          string `json:"skin_url"`
+	CapeURL          string `json:"cape_url"`
+	UserAgent        string `json:"user_agent"`
+
+	Timeouts TimeoutConfig `json:"timeouts"`
+	Limits   LimitConfig   `json:"limits"`
+	Cache    CacheConfig   `json:"cache"`
+
+	// TexturePolicy restricts the URLs textures are fetched from
+	TexturePolicy *URLPolicy `json:"texture_policy"`
+
+	// FallbackSkin is the skin to serve when a player's can't be fetched,
+	// "default" (Steve or Alex, as the game picked them before 1.19.3),
+	// "steve" or "none"
+	FallbackSkin string `json:"fallback_skin"`
+}
+
+// TimeoutConfig is how long requests may take
+type TimeoutConfig struct {
+	// Request is the limit on the whole request (10s by default)
+	Request        Duration `json:"request"`
+	Connect        Duration `json:"connect"`
+	TLSHandshake   Duration `json:"tls_handshake"`
+	ResponseHeader Duration `json:"response_header"`
+}
+
+// LimitConfig limits the resources used
+type LimitConfig struct {
+	// MaxTextureBytes is the largest texture which will be downloaded
+	MaxTextureBytes int64 `json:"max_texture_bytes"`
+	// MaxConnsPerHost limits the connections made to each host
+	MaxConnsPerHost int `json:"max_conns_per_host"`
+}
+
+// CacheConfig is how API responses are cached (see ResponseCache), and how
+// long responses may be cached by clients of a handler
+type CacheConfig struct {
+	// ResponseTTL, if set, caches API responses for that long
+	ResponseTTL          Duration `json:"response_ttl"`
+	StaleWhileRevalidate bool     `json:"stale_while_revalidate"`
+	MaxStale             Duration `json:"max_stale"`
+	MaxEntries           int      `json:"max_entries"`
+
+	MaxAge                 Duration `json:"max_age"`
+	FallbackMaxAge         Duration `json:"fallback_max_age"`
+	ContentAddressedMaxAge Duration `json:"content_addressed_max_age"`
+}
+
+// configEnv are the environment variables read by ApplyEnv
+var configEnv = []struct {
+	name string
+	set  func(c *Config, value string) error
+}{
+	{"MINECRAFT_PRESET", func(c *Config, v string) error { c.Preset = v; return nil }},
+	{"MINECRAFT_PROFILE_URL", func(c *Config, v string) error { c.ProfileURL = v; return nil }},
+	{"MINECRAFT_SESSION_SERVER_URL", func(c *Config, v string) error { c.SessionServerURL = v; return nil }},
+	{"MINECRAFT_SKIN_URL", func(c *Config, v string) error { c.SkinURL = v; return nil }},
+	{"MINECRAFT_CAPE_URL", func(c *Config, v string) error { c.CapeURL = v; return nil }},
+	{"MINECRAFT_USER_AGENT", func(c *Config, v string) error { c.UserAgent = v; return nil }},
+	{"MINECRAFT_TIMEOUT", durationEnv(func(c *Config) *Duration { return &c.Timeouts.Request })},
+	{"MINECRAFT_CONNECT_TIMEOUT", durationEnv(func(c *Config) *Duration { return &c.Timeouts.Connect })},
+	{"MINECRAFT_TLS_HANDSHAKE_TIMEOUT", durationEnv(func(c *Config) *Duration { return &c.Timeouts.TLSHandshake })},
+	{"MINECRAFT_RESPONSE_HEADER_TIMEOUT", durationEnv(func(c *Config) *Duration { return &c.Timeouts.ResponseHeader })},
+	{"MINECRAFT_MAX_TEXTURE_BYTES", func(c *Config, v string) error {
+		n, err := strconv.ParseInt(v, 10, 64)
+		c.Limits.MaxTextureBytes = n
+		return err
+	}},
+	{"MINECRAFT_MAX_CONNS_PER_HOST", func(c *Config, v string) error {
+		n, err := strconv.Atoi(v)
+		c.Limits.MaxConnsPerHost = n
+		return err
+	}},
+	{"MINECRAFT_CACHE_RESPONSE_TTL", durationEnv(func(c *Config) *Duration { return &c.Cache.ResponseTTL })},
+	{"MINECRAFT_CACHE_STALE_WHILE_REVALIDATE", func(c *Config, v string) error {
+		b, err := strconv.ParseBool(v)
+		c.Cache.StaleWhileRevalidate = b
+		return err
+	}},
+	{"MINECRAFT_CACHE_MAX_STALE", durationEnv(func(c *Config) *Duration { return &c.Cache.MaxStale })},
+	{"MINECRAFT_CACHE_MAX_ENTRIES", func(c *Config, v string) error {
+		n, err := strconv.Atoi(v)
+		c.Cache.MaxEntries = n
+		return err
+	}},
+	{"MINECRAFT_CACHE_MAX_AGE", durationEnv(func(c *Config) *Duration { return &c.Cache.MaxAge })},
+	{"MINECRAFT_CACHE_FALLBACK_MAX_AGE", durationEnv(func(c *Config) *Duration { return &c.Cache.FallbackMaxAge })},
+	{"MINECRAFT_CACHE_CONTENT_ADDRESSED_MAX_AGE", durationEnv(func(c *Config) *Duration { return &c.Cache.ContentAddressedMaxAge })},
+	{"MINECRAFT_FALLBACK_SKIN", func(c *Config, v string) error { c.FallbackSkin = v; return nil }},
+}
+
+func durationEnv(field func(c *Config) *Duration) func(c *Config, value string) error {
+	return func(c *Config, value string) error {
+		d, err := time.ParseDuration(value)
+		*field(c) = Duration(d)
+		return err
+	}
+}
+
+// LoadConfig reads the JSON config file (if file isn't empty), overrides it
+// with any MINECRAFT_* environment variables and validates it
+func LoadConfig(file string) (Config, error) {
+	var config Config
+	if file != "" {
+		f, err := os.Open(file)
+		if err != nil {
+			return config, errors.Wrap(err, "unable to LoadConfig")
+		}
+		defer f.Close()
+
+		decoder := json.NewDecoder(f)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&config); err != nil {
+			return config, errors.Wrapf(err, "unable to LoadConfig from %s", file)
+		}
+	}
+
+	if err := config.ApplyEnv(os.LookupEnv); err != nil {
+		return config, errors.Wrap(err, "unable to LoadConfig")
+	}
+	if err := config.Validate(); err != nil {
+		return config, errors.Wrap(err, "unable to LoadConfig")
+	}
+	return config, nil
+}
+
+// ApplyEnv overrides the config with the MINECRAFT_* variables found with
+// lookup (eg. os.LookupEnv)
+func (c *Config) ApplyEnv(lookup func(name string) (string, bool)) error {
+	for _, env := range configEnv {
+		value, ok := lookup(env.name)
+		if !ok {
+			continue
+		}
+		if err := env.set(c, value); err != nil {
+			return errors.Wrapf(err, "invalid %s", env.name)
+		}
+	}
+	return nil
+}
+
+// Validate checks the config makes sense
+func (c Config) Validate() error {
+	preset, ok := Presets[c.preset()]
+	if !ok {
+		return errors.Errorf("unknown preset %q", c.Preset)
+	}
+
+	urls := []struct{ name, value string }{
+		{"profile_url", pick(c.ProfileURL, preset.ProfileURL)},
+		{"session_server_url", pick(c.SessionServerURL, preset.SessionServerURL)},
+		{"skin_url", pick(c.SkinURL, preset.SkinURL)},
+		{"cape_url", pick(c.CapeURL, preset.CapeURL)},
+	}
+	for _, u := range urls {
+		if u.value == "" {
+			if u.name == "profile_url" || u.name == "session_server_url" {
+				return errors.Errorf("%s is required", u.name)
+			}
+			continue
+		}
+		parsed, err := url.Parse(u.value)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return errors.Errorf("%s must be an http or https URL (got %q)", u.name, u.value)
+		}
+		if !strings.HasSuffix(u.value, "/") {
+			return errors.Errorf("%s must end with \"/\" as the player is appended (got %q)", u.name, u.value)
+		}
+	}
+
+	durations := []struct {
+		name  string
+		value Duration
+	}{
+		{"timeouts.request", c.Timeouts.Request},
+		{"timeouts.connect", c.Timeouts.Connect},
+		{"timeouts.tls_handshake", c.Timeouts.TLSHandshake},
+		{"timeouts.response_header", c.Timeouts.ResponseHeader},
+		{"cache.response_ttl", c.Cache.ResponseTTL},
+		{"cache.max_stale", c.Cache.MaxStale},
+		{"cache.max_age", c.Cache.MaxAge},
+		{"cache.fallback_max_age", c.Cache.FallbackMaxAge},
+		{"cache.content_addressed_max_age", c.Cache.ContentAddressedMaxAge},
+	}
+	for _, d := range durations {
+		if d.value < 0 {
+			return errors.Errorf("%s must not be negative (got %s)", d.name, time.Duration(d.value))
+		}
+	}
+
+	if c.Cache.MaxEntries < 0 {
+		return errors.Errorf("cache.max_entries must not be negative (got %d)", c.Cache.MaxEntries)
+	}
+	if c.Cache.StaleWhileRevalidate && c.Cache.ResponseTTL == 0 {
+		return errors.New("cache.stale_while_revalidate needs a cache.response_ttl")
+	}
+	if c.Limits.MaxTextureBytes < 0 {
+		return errors.Errorf("limits.max_texture_bytes must not be negative (got %d)", c.Limits.MaxTextureBytes)
+	}
+	if c.Limits.MaxConnsPerHost < 0 {
+		return errors.Errorf("limits.max_conns_per_host must not be negative (got %d)", c.Limits.MaxConnsPerHost)
+	}
+
+	if policy := c.TexturePolicy; policy != nil {
+		for _, scheme := range policy.Schemes {
+			if scheme != "http" && scheme != "https" {
+				return errors.Errorf("texture_policy.schemes must be \"http\" or \"https\" (got %q)", scheme)
+			}
+		}
+		for _, port := range policy.Ports {
+			if port < 1 || port > 65535 {
+				return errors.Errorf("texture_policy.ports must be between 1 and 65535 (got %d)", port)
+			}
+		}
+	}
+
+	switch c.FallbackSkin {
+	case "", "default", "steve", "none":
+	default:
+		return errors.Errorf("fallback_skin must be \"default\", \"steve\" or \"none\" (got %q)", c.FallbackSkin)
+	}
+	return nil
+}
+
+func (c Config) preset() string {
+	return pick(c.Preset, DefaultPreset)
+}
+
+// pick returns value, or def if it is empty
+func pick(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
+
+// NewMinecraft returns a Minecraft configured by c
+func (c Config) NewMinecraft() (*Minecraft, error) {
+	if err := c.Validate(); err != nil {
+		return nil, errors.Wrap(err, "unable to NewMinecraft")
+	}
+	preset := Presets[c.preset()]
+
+	mc := NewMinecraft()
+	mc.UUIDAPI = UUIDAPI{
+		SessionServerURL: pick(c.SessionServerURL, preset.SessionServerURL),
+		ProfileURL:       pick(c.ProfileURL, preset.ProfileURL),
+	}
+	mc.UsernameAPI = UsernameAPI{
+		SkinURL: pick(c.SkinURL, preset.SkinURL),
+		CapeURL: pick(c.CapeURL, preset.CapeURL),
+	}
+	mc.UserAgent = pick(c.UserAgent, mc.UserAgent)
+	mc.MaxTextureBytes = c.Limits.MaxTextureBytes
+	mc.TexturePolicy = c.TexturePolicy
+	if c.Cache.ResponseTTL != 0 {
+		mc.Cache = &ResponseCache{
+			TTL:                  time.Duration(c.Cache.ResponseTTL),
+			StaleWhileRevalidate: c.Cache.StaleWhileRevalidate,
+			MaxStale:             time.Duration(c.Cache.MaxStale),
+			MaxEntries:           c.Cache.MaxEntries,
+		}
+	}
+
+	if c.Timeouts.Request != 0 {
+		mc.Client.Timeout = time.Duration(c.Timeouts.Request)
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c.Timeouts.Connect != 0 {
+		dialer := &net.Dialer{Timeout: time.Duration(c.Timeouts.Connect), KeepAlive: 30 * time.Second}
+		transport.DialContext = dialer.DialContext
+	}
+	if c.Timeouts.TLSHandshake != 0 {
+		transport.TLSHandshakeTimeout = time.Duration(c.Timeouts.TLSHandshake)
+	}
+	transport.ResponseHeaderTimeout = time.Duration(c.Timeouts.ResponseHeader)
+	transport.MaxConnsPerHost = c.Limits.MaxConnsPerHost
+	mc.Client.Transport = transport
+
+	return mc, nil
+}
@@ -0,0 +1,195 @@
+// config_test.go
+package minecraft
+
+import (
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestConfig(t *testing.T) {
+
+	Convey("Test LoadConfig", t, func() {
+
+		dir, _ := ioutil.TempDir("", "minecraft")
+		defer os.RemoveAll(dir)
+		file := filepath.Join(dir, "config.json")
+
+		Convey("No file should give the defaults", func() {
+			config, err := LoadConfig("")
+			So(err, ShouldBeNil)
+
+			mc, err := config.NewMinecraft()
+			So(err, ShouldBeNil)
+			So(mc.UUIDAPI, ShouldResemble, NewMinecraft().UUIDAPI)
+			So(mc.UsernameAPI, ShouldResemble, UsernameAPI{})
+			So(mc.UserAgent, ShouldEqual, NewMinecraft().UserAgent)
+			So(mc.Client.Timeout, ShouldEqual, 10*time.Second)
+		})
+
+		Convey("A file should be loaded", func() {
+			ioutil.WriteFile(file, []byte(`{
+				"preset": "elyby",
+				"user_agent": "test",
+				"timeouts": {"request": "3s", "tls_handshake": "2s"},
+				"limits": {"max_texture_bytes": 1024, "max_conns_per_host": 4},
+				"cache": {"max_age": "5m"},
+				"fallback_skin": "none"
+			}`), 0644)
+
+			config, err := LoadConfig(file)
+			So(err, ShouldBeNil)
+			So(config.Cache.MaxAge, ShouldEqual, Duration(5*time.Minute))
+			So(config.FallbackSkin, ShouldEqual, "none")
+
+			mc, err := config.NewMinecraft()
+			So(err, ShouldBeNil)
+			So(mc.UUIDAPI, ShouldResemble, Presets["elyby"].UUIDAPI)
+			So(mc.UsernameAPI, ShouldResemble, Presets["elyby"].UsernameAPI)
+			So(mc.UserAgent, ShouldEqual, "test")
+			So(mc.MaxTextureBytes, ShouldEqual, 1024)
+			So(mc.Client.Timeout, ShouldEqual, 3*time.Second)
+			So(mc.Cache, ShouldBeNil)
+
+			transport := mc.Client.Transport.(*http.Transport)
+			So(transport.TLSHandshakeTimeout, ShouldEqual, 2*time.Second)
+			So(transport.MaxConnsPerHost, ShouldEqual, 4)
+		})
+
+		Convey("A response cache should be configured", func() {
+			ioutil.WriteFile(file, []byte(`{"cache": {"response_ttl": "1m", "stale_while_revalidate": true, "max_entries": 100}}`), 0644)
+
+			config, err := LoadConfig(file)
+			So(err, ShouldBeNil)
+
+			mc, err := config.NewMinecraft()
+			So(err, ShouldBeNil)
+			So(mc.Cache.TTL, ShouldEqual, time.Minute)
+			So(mc.Cache.StaleWhileRevalidate, ShouldBeTrue)
+			So(mc.Cache.MaxEntries, ShouldEqual, 100)
+		})
+
+		Convey("Bad files should fail", func() {
+			_, err := LoadConfig(filepath.Join(dir, "missing.json"))
+			So(err, ShouldNotBeNil)
+
+			ioutil.WriteFile(file, []byte(`{"timeouts": {"request": 10}}`), 0644)
+			_, err = LoadConfig(file)
+			So(err, ShouldNotBeNil)
+
+			ioutil.WriteFile(file, []byte(`{"unknown": true}`), 0644)
+			_, err = LoadConfig(file)
+			So(err, ShouldNotBeNil)
+
+			ioutil.WriteFile(file, []byte(`{"preset": "minotar"}`), 0644)
+			_, err = LoadConfig(file)
+			So(err.Error(), ShouldEqual, `unable to LoadConfig: unknown preset "minotar"`)
+		})
+
+	})
+
+	Convey("Test ApplyEnv", t, func() {
+
+		env := map[string]string{
+			"MINECRAFT_PROFILE_URL":       "http://localhost/profiles/",
+			"MINECRAFT_TIMEOUT":           "1m",
+			"MINECRAFT_MAX_TEXTURE_BYTES": "2048",
+			"MINECRAFT_CACHE_MAX_AGE":     "30s",
+		}
+		lookup := func(name string) (string, bool) {
+			value, ok := env[name]
+			return value, ok
+		}
+
+		Convey("Variables should override the config", func() {
+			config := Config{ProfileURL: "http://example.com/", UserAgent: "test"}
+			So(config.ApplyEnv(lookup), ShouldBeNil)
+
+			So(config, ShouldResemble, Config{
+				ProfileURL: "http://localhost/profiles/",
+				UserAgent:  "test",
+				Timeouts:   TimeoutConfig{Request: Duration(time.Minute)},
+				Limits:     LimitConfig{MaxTextureBytes: 2048},
+				Cache:      CacheConfig{MaxAge: Duration(30 * time.Second)},
+			})
+		})
+
+		Convey("Bad variables should fail", func() {
+			env["MINECRAFT_TIMEOUT"] = "soon"
+
+			config := Config{}
+			err := config.ApplyEnv(lookup)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldStartWith, "invalid MINECRAFT_TIMEOUT")
+		})
+
+	})
+
+	Convey("Test Validate", t, func() {
+
+		Convey("Presets should be valid", func() {
+			for name := range Presets {
+				So(Config{Preset: name}.Validate(), ShouldBeNil)
+			}
+		})
+
+		Convey("Presets should only use https", func() {
+			for _, preset := range Presets {
+				for _, url := range []string{preset.SessionServerURL, preset.ProfileURL, preset.SkinURL, preset.CapeURL} {
+					if url != "" {
+						So(url, ShouldStartWith, "https://")
+					}
+				}
+			}
+		})
+
+		Convey("Bad configs should fail", func() {
+			bad := map[string]Config{
+				`profile_url must be an http or https URL (got "ftp://example.com/")`:             {ProfileURL: "ftp://example.com/"},
+				`skin_url must end with "/" as the player is appended (got "http://example.com")`: {SkinURL: "http://example.com"},
+				`timeouts.connect must not be negative (got -1s)`:                                 {Timeouts: TimeoutConfig{Connect: Duration(-time.Second)}},
+				`limits.max_texture_bytes must not be negative (got -1)`:                          {Limits: LimitConfig{MaxTextureBytes: -1}},
+				`texture_policy.ports must be between 1 and 65535 (got 0)`:                        {TexturePolicy: &URLPolicy{Ports: []int{0}}},
+				`cache.stale_while_revalidate needs a cache.response_ttl`:                         {Cache: CacheConfig{StaleWhileRevalidate: true}},
+				`fallback_skin must be "default", "steve" or "none" (got "alex")`:                 {FallbackSkin: "alex"},
+			}
+			for msg, config := range bad {
+				err := config.Validate()
+				So(err, ShouldNotBeNil)
+				So(err.Error(), ShouldEqual, msg)
+
+				_, err = config.NewMinecraft()
+				So(err, ShouldNotBeNil)
+			}
+		})
+
+	})
+
+	Convey("Test MaxTextureBytes", t, func() {
+
+		mc := *mcTest
+
+		Convey("Textures larger than the limit should fail", func() {
+			mc.MaxTextureBytes = 100
+			skin := &Skin{Texture{Mc: &mc}}
+
+			err := skin.FetchWithUsername("citricsquid", "Skin")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "FetchWithUsername failed: unable to Fetch Texture: larger than 100 bytes")
+		})
+
+		Convey("Textures within the limit should be fetched", func() {
+			mc.MaxTextureBytes = 1 << 20
+			skin := &Skin{Texture{Mc: &mc}}
+
+			So(skin.FetchWithUsername("citricsquid", "Skin"), ShouldBeNil)
+		})
+
+	})
+
+}
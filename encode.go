@@ -0,0 +1,276 @@
+package minecraft
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+
+	"github.com/pkg/errors"
+)
+
+// PNGOptions controls how EncodePNG writes images
+type PNGOptions struct {
+	// Compression level for the encoder (the zero value is the default level)
+	Compression png.CompressionLevel
+	// OptimizePalette writes a paletted PNG when the image has no more than
+	// 256 colours, which is most skins and renders
+	OptimizePalette bool
+}
+
+// EncodePNG writes the image as a PNG
+func EncodePNG(w io.Writer, img image.Image, opts PNGOptions) error {
+	if img == nil {
+		return errors.New("unable to EncodePNG: no image")
+	}
+
+	if opts.OptimizePalette {
+		if pal, ok := exactPalette(img, 256); ok {
+			img = exactPaletted(img, pal)
+		}
+	}
+
+	encoder := png.Encoder{CompressionLevel: opts.Compression}
+	if err := encoder.Encode(w, img); err != nil {
+		return errors.Wrap(err, "unable to EncodePNG")
+	}
+	return nil
+}
+
+// EncodeGIF writes the image as a GIF. GIFs only have on/off transparency, so
+// given a background the image is flattened onto it, otherwise mostly
+// transparent pixels are made fully transparent and the rest opaque.
+func EncodeGIF(w io.Writer, img image.Image, background color.Color) error {
+	if img == nil {
+		return errors.New("unable to EncodeGIF: no image")
+	}
+
+	if err := gif.Encode(w, palettize(img, background), nil); err != nil {
+		return errors.Wrap(err, "unable to EncodeGIF")
+	}
+	return nil
+}
+
+// EncodeJPEG writes the image as a JPEG flattened onto the background (white
+// if nil) as JPEGs have no transparency. A quality of 0 uses the default.
+func EncodeJPEG(w io.Writer, img image.Image, quality int, background color.Color) error {
+	if img == nil {
+		return errors.New("unable to EncodeJPEG: no image")
+	}
+	if background == nil {
+		background = color.White
+	}
+	if quality == 0 {
+		quality = jpeg.DefaultQuality
+	}
+
+	if err := jpeg.Encode(w, flatten(img, background), &jpeg.Options{Quality: quality}); err != nil {
+		return errors.Wrap(err, "unable to EncodeJPEG")
+	}
+	return nil
+}
+
+// EncodeICO writes the images (each no larger than 256x256) as the sizes of
+// an ICO, stored as PNGs
+func EncodeICO(w io.Writer, images []image.Image) error {
+	if len(images) == 0 {
+		return errors.New("unable to EncodeICO: no images")
+	}
+
+	var data [][]byte
+	for _, img := range images {
+		bounds := img.Bounds()
+		if bounds.Dx() < 1 || bounds.Dy() < 1 || bounds.Dx() > 256 || bounds.Dy() > 256 {
+			return errors.Errorf("unable to EncodeICO: image size %dx%d must be between 1x1 and 256x256", bounds.Dx(), bounds.Dy())
+		}
+
+		buf := &bytes.Buffer{}
+		if err := png.Encode(buf, img); err != nil {
+			return errors.Wrap(err, "unable to EncodeICO")
+		}
+		data = append(data, buf.Bytes())
+	}
+
+	// ICONDIR, then an ICONDIRENTRY for each image, then the image data
+	header := &bytes.Buffer{}
+	binary.Write(header, binary.LittleEndian, []uint16{0, 1, uint16(len(images))})
+
+	offset := 6 + 16*len(images)
+	for i, img := range images {
+		bounds := img.Bounds()
+		binary.Write(header, binary.LittleEndian, struct {
+			Width, Height, Colors, Reserved uint8
+			Planes, BitCount                uint16
+			Size, Offset                    uint32
+		}{
+			// A dimension of 256 is stored as 0
+			Width:    uint8(bounds.Dx()),
+			Height:   uint8(bounds.Dy()),
+			Planes:   1,
+			BitCount: 32,
+			Size:     uint32(len(data[i])),
+			Offset:   uint32(offset),
+		})
+		offset += len(data[i])
+	}
+
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return errors.Wrap(err, "unable to EncodeICO")
+	}
+	for _, d := range data {
+		if _, err := w.Write(d); err != nil {
+			return errors.Wrap(err, "unable to EncodeICO")
+		}
+	}
+	return nil
+}
+
+// EncodePNG writes the texture image as a PNG
+func (t *Texture) EncodePNG(w io.Writer, opts PNGOptions) error {
+	return EncodePNG(w, t.Image, opts)
+}
+
+// EncodeFavicon writes an ICO of the head at each of the sizes (16, 32 and 48
+// pixels if none are given)
+func (s *Skin) EncodeFavicon(w io.Writer, overlay bool, sizes ...int) error {
+	if len(sizes) == 0 {
+		sizes = []int{16, 32, 48}
+	}
+
+	var images []image.Image
+	for _, size := range sizes {
+		if size < 1 || size > 256 {
+			return errors.Errorf("unable to EncodeFavicon: size %d must be between 1 and 256", size)
+		}
+
+		head, err := s.RenderHead((size+7)/8, overlay)
+		if err != nil {
+			return errors.Wrap(err, "unable to EncodeFavicon")
+		}
+		images = append(images, resizeNearest(head, size, size))
+	}
+
+	return EncodeICO(w, images)
+}
+
+// exactPalette returns every colour in the image, as long as there are no more
+// than max of them
+func exactPalette(img image.Image, max int) (color.Palette, bool) {
+	seen := make(map[color.NRGBA]bool)
+	var pal color.Palette
+
+	bounds := img.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A == 0 {
+				// All transparent pixels are the same to us
+				c = color.NRGBA{}
+			}
+			if seen[c] {
+				continue
+			}
+			if len(pal) == max {
+				return nil, false
+			}
+			seen[c] = true
+			pal = append(pal, c)
+		}
+	}
+	return pal, true
+}
+
+// exactPaletted indexes every pixel of the image by its exact colour in the
+// palette. Palette.Index compares premultiplied colours, so it would merge
+// translucent colours that only differ before premultiplication.
+func exactPaletted(img image.Image, pal color.Palette) *image.Paletted {
+	indexes := make(map[color.NRGBA]uint8, len(pal))
+	for i, c := range pal {
+		indexes[c.(color.NRGBA)] = uint8(i)
+	}
+
+	bounds := img.Bounds()
+	paletted := image.NewPaletted(bounds, pal)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A == 0 {
+				c = color.NRGBA{}
+			}
+			paletted.SetColorIndex(x, y, indexes[c])
+		}
+	}
+	return paletted
+}
+
+// flatten draws the image over a solid background
+func flatten(img image.Image, background color.Color) *image.NRGBA {
+	bounds := img.Bounds()
+	flat := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(flat, flat.Rect, image.NewUniform(background), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Rect, img, bounds.Min, draw.Over)
+	return flat
+}
+
+// palettize converts the image for formats limited to 256 colours with on/off
+// transparency. Without a background, index 0 is transparent.
+func palettize(img image.Image, background color.Color) *image.Paletted {
+	bounds := img.Bounds()
+	var src *image.NRGBA
+	if background != nil {
+		src = flatten(img, background)
+	} else {
+		// Snap the alpha so every pixel is either in or out
+		src = image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+		for y := 0; y < src.Rect.Dy(); y++ {
+			for x := 0; x < src.Rect.Dx(); x++ {
+				c := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+				if c.A < 0x80 {
+					c = color.NRGBA{}
+				} else {
+					c.A = 0xff
+				}
+				src.SetNRGBA(x, y, c)
+			}
+		}
+	}
+
+	pal, exact := exactPalette(src, 256)
+	if exact {
+		if background == nil {
+			pal = withTransparentFirst(pal)
+		}
+	} else {
+		// Too many colours, so dither to the web safe colours instead
+		pal = append(color.Palette{color.NRGBA{}}, palette.WebSafe...)
+	}
+
+	paletted := image.NewPaletted(src.Rect, pal)
+	if exact {
+		draw.Draw(paletted, paletted.Rect, src, image.Point{}, draw.Src)
+	} else {
+		draw.FloydSteinberg.Draw(paletted, paletted.Rect, src, image.Point{})
+	}
+	return paletted
+}
+
+// withTransparentFirst moves (or adds) the transparent colour to index 0
+func withTransparentFirst(pal color.Palette) color.Palette {
+	sorted := color.Palette{color.NRGBA{}}
+	for _, c := range pal {
+		if c != (color.NRGBA{}) {
+			sorted = append(sorted, c)
+		}
+	}
+	if len(sorted) > 256 {
+		// The image had 256 opaque colours, so lose one to make room
+		sorted = sorted[:256]
+	}
+	return sorted
+}
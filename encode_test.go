@@ -0,0 +1,153 @@
+// encode_test.go
+package minecraft
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestEncode(t *testing.T) {
+	steve, _ := FetchSkinForSteve()
+
+	Convey("Test EncodePNG", t, func() {
+
+		Convey("Steve should survive a round trip", func() {
+			buf := &bytes.Buffer{}
+			err := steve.EncodePNG(buf, PNGOptions{Compression: png.BestCompression})
+			So(err, ShouldBeNil)
+
+			skin := &Skin{}
+			So(skin.Decode(buf), ShouldBeNil)
+			So(skin.Hash, ShouldEqual, SteveHash)
+		})
+
+		Convey("Optimizing the palette should still give the same visible pixels", func() {
+			buf := &bytes.Buffer{}
+			err := steve.EncodePNG(buf, PNGOptions{OptimizePalette: true})
+			So(err, ShouldBeNil)
+
+			img, _ := png.Decode(bytes.NewReader(buf.Bytes()))
+			_, paletted := img.(*image.Paletted)
+			So(paletted, ShouldBeTrue)
+
+			So(sameVisiblePixels(img, steve.Image), ShouldBeTrue)
+		})
+
+		Convey("Optimizing the palette should keep translucent colours apart", func() {
+			// Both colours are the same once premultiplied
+			src := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+			src.SetNRGBA(0, 0, color.NRGBA{0, 0, 0, 1})
+			src.SetNRGBA(1, 0, color.NRGBA{1, 0, 0, 1})
+
+			buf := &bytes.Buffer{}
+			So(EncodePNG(buf, src, PNGOptions{OptimizePalette: true}), ShouldBeNil)
+
+			img, _ := png.Decode(bytes.NewReader(buf.Bytes()))
+			So(color.NRGBAModel.Convert(img.At(0, 0)), ShouldResemble, color.NRGBA{0, 0, 0, 1})
+			So(color.NRGBAModel.Convert(img.At(1, 0)), ShouldResemble, color.NRGBA{1, 0, 0, 1})
+		})
+
+		Convey("Textures without an image should gracefully fail", func() {
+			err := (&Texture{}).EncodePNG(&bytes.Buffer{}, PNGOptions{})
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to EncodePNG: no image")
+		})
+
+	})
+
+	Convey("Test EncodeGIF and EncodeJPEG", t, func() {
+		head, _ := steve.RenderHeadIso(32, true)
+
+		Convey("GIFs should keep the transparency", func() {
+			buf := &bytes.Buffer{}
+			So(EncodeGIF(buf, head, nil), ShouldBeNil)
+
+			img, err := gif.Decode(buf)
+			So(err, ShouldBeNil)
+			_, _, _, a := img.At(0, 0).RGBA()
+			So(a, ShouldEqual, 0)
+			_, _, _, a = img.At(16, 16).RGBA()
+			So(a, ShouldEqual, 0xffff)
+		})
+
+		Convey("GIFs should be filled with the background", func() {
+			buf := &bytes.Buffer{}
+			So(EncodeGIF(buf, head, color.NRGBA{255, 0, 0, 255}), ShouldBeNil)
+
+			img, _ := gif.Decode(buf)
+			So(color.NRGBAModel.Convert(img.At(0, 0)), ShouldResemble, color.NRGBA{255, 0, 0, 255})
+		})
+
+		Convey("JPEGs should be filled with the background", func() {
+			buf := &bytes.Buffer{}
+			So(EncodeJPEG(buf, head, 100, nil), ShouldBeNil)
+
+			img, err := jpeg.Decode(buf)
+			So(err, ShouldBeNil)
+			So(img.Bounds(), ShouldResemble, image.Rect(0, 0, 32, 32))
+			r, g, b, _ := img.At(0, 0).RGBA()
+			So(r>>8, ShouldBeGreaterThan, 250)
+			So(g>>8, ShouldBeGreaterThan, 250)
+			So(b>>8, ShouldBeGreaterThan, 250)
+		})
+
+	})
+
+	Convey("Test EncodeFavicon", t, func() {
+
+		Convey("Steve should give a favicon with three sizes", func() {
+			buf := &bytes.Buffer{}
+			So(steve.EncodeFavicon(buf, true), ShouldBeNil)
+
+			var header [3]uint16
+			binary.Read(bytes.NewReader(buf.Bytes()), binary.LittleEndian, &header)
+			So(header, ShouldResemble, [3]uint16{0, 1, 3})
+
+			// The first entry should be 16x16 with a PNG after the directory
+			So(buf.Bytes()[6], ShouldEqual, 16)
+			So(buf.Bytes()[22], ShouldEqual, 32)
+			offset := binary.LittleEndian.Uint32(buf.Bytes()[18:22])
+			So(offset, ShouldEqual, 6+16*3)
+
+			img, err := png.Decode(bytes.NewReader(buf.Bytes()[offset:]))
+			So(err, ShouldBeNil)
+			So(img.Bounds(), ShouldResemble, image.Rect(0, 0, 16, 16))
+		})
+
+		Convey("Bad sizes should gracefully fail", func() {
+			err := steve.EncodeFavicon(&bytes.Buffer{}, true, 512)
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to EncodeFavicon: size 512 must be between 1 and 256")
+		})
+
+	})
+
+}
+
+// sameVisiblePixels compares two images, ignoring the colour of transparent pixels
+func sameVisiblePixels(a, b image.Image) bool {
+	if a.Bounds() != b.Bounds() {
+		return false
+	}
+	bounds := a.Bounds()
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			ca := color.NRGBAModel.Convert(a.At(x, y)).(color.NRGBA)
+			cb := color.NRGBAModel.Convert(b.At(x, y)).(color.NRGBA)
+			if ca != cb && (ca.A != 0 || cb.A != 0) {
+				return false
+			}
+		}
+	}
+	return true
+}
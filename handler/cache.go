@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minotar/minecraft"
+)
+
+const (
+	// DefaultFallbackMaxAge is how long fallback skins may be cached, as the
+	// player might fix whatever stopped us getting theirs
+	DefaultFallbackMaxAge = 10 * time.Minute
+	// DefaultMaxAge is how long textures may be cached
+	DefaultMaxAge = time.Hour
+	// DefaultContentAddressedMaxAge is how long textures named by their hash
+	// (as Mojang's are) may be cached, as they only change with a new skin
+	DefaultContentAddressedMaxAge = 24 * time.Hour
+)
+
+// maxAge picks how long a response for the texture may be cached. Stale
+// textures are cached as briefly as fallbacks, so the fresh one is soon seen.
+func (h *Handler) maxAge(texture minecraft.Texture, fallback bool) time.Duration {
+	if fallback || texture.Stale {
+		return h.FallbackMaxAge
+	}
+	if texture.Source == "SessionProfile" {
+		if _, err := minecraft.TextureIDFromURL(texture.URL); err == nil {
+			return h.ContentAddressedMaxAge
+		}
+	}
+	return h.MaxAge
+}
+
+// checkCache sets the caching headers for a response made from the texture.
+// When the client already has it, a 304 is written and true returned.
+func (h *Handler) checkCache(w http.ResponseWriter, r *http.Request, texture minecraft.Texture, fallback bool, variant string) bool {
+	etag := `"` + texture.Hash
+	if variant != "" {
+		etag += "-" + variant
+	}
+	etag += `"`
+
+	header := w.Header()
+	header.Set("ETag", etag)
+	header.Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(h.maxAge(texture, fallback).Seconds())))
+	if !texture.Timestamp.IsZero() {
+		header.Set("Last-Modified", texture.Timestamp.UTC().Format(http.TimeFormat))
+	}
+
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// etagMatches reports whether the If-None-Match header includes the ETag,
+// using the weak comparison
+func etagMatches(ifNoneMatch string, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// variant names the render for the ETag, eg. "helm-64"
+func (req request) variant() string {
+	switch req.route {
+	case "skin", "cape":
+		return ""
+	}
+	return req.route + "-" + strconv.Itoa(req.size)
+}
@@ -0,0 +1,95 @@
+// cache_test.go
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/minotar/minecraft"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+func TestCache(t *testing.T) {
+
+	Convey("Test caching headers", t, func() {
+
+		Convey("Renders should have an ETag of the skin and variant", func() {
+			rec := get("/helm/clone1018/64")
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(rec.Header().Get("ETag"), ShouldEqual, `"a04a26d10218668a632e419ab073cf57-helm-64"`)
+			// Mojang textures are named by their hash
+			So(rec.Header().Get("Cache-Control"), ShouldEqual, "public, max-age=86400")
+		})
+
+		Convey("Textures should have an ETag of the hash", func() {
+			rec := get("/skin/clone1018")
+
+			So(rec.Header().Get("ETag"), ShouldEqual, `"a04a26d10218668a632e419ab073cf57"`)
+		})
+
+		Convey("Fallbacks should be cached for less time", func() {
+			rec := get("/avatar/10000000000000000000000000000000")
+
+			So(rec.Header().Get("ETag"), ShouldEqual, `"`+minecraft.SteveHash+`-avatar-180"`)
+			So(rec.Header().Get("Cache-Control"), ShouldEqual, "public, max-age=600")
+		})
+
+		Convey("Stale textures should be cached for less time", func() {
+			rec := httptest.NewRecorder()
+			texture := minecraft.Texture{Hash: "a04a26d10218668a632e419ab073cf57", Source: "SessionProfile", Stale: true}
+			New(mcTest).checkCache(rec, httptest.NewRequest("GET", "/skin/clone1018", nil), texture, false, "")
+
+			So(rec.Header().Get("Cache-Control"), ShouldEqual, "public, max-age=600")
+		})
+
+		Convey("Matching If-None-Match should get a 304", func() {
+			for _, ifNoneMatch := range []string{
+				`"a04a26d10218668a632e419ab073cf57-helm-64"`,
+				`W/"a04a26d10218668a632e419ab073cf57-helm-64"`,
+				`"other", "a04a26d10218668a632e419ab073cf57-helm-64"`,
+				`*`,
+			} {
+				req := httptest.NewRequest("GET", "/helm/clone1018/64", nil)
+				req.Header.Set("If-None-Match", ifNoneMatch)
+				rec := httptest.NewRecorder()
+				New(mcTest).ServeHTTP(rec, req)
+
+				So(rec.Code, ShouldEqual, http.StatusNotModified)
+				So(rec.Body.Len(), ShouldEqual, 0)
+				So(rec.Header().Get("ETag"), ShouldEqual, `"a04a26d10218668a632e419ab073cf57-helm-64"`)
+			}
+		})
+
+		Convey("Other If-None-Match should get the image", func() {
+			req := httptest.NewRequest("GET", "/helm/clone1018/64", nil)
+			req.Header.Set("If-None-Match", `"a04a26d10218668a632e419ab073cf57-helm-32"`)
+			rec := httptest.NewRecorder()
+			New(mcTest).ServeHTTP(rec, req)
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+		})
+
+		Convey("Last-Modified should be the textures property timestamp", func() {
+			h := New(mcTest)
+			texture := minecraft.Texture{Hash: "abc", Timestamp: time.Unix(1500000000, 123000000)}
+			rec := httptest.NewRecorder()
+
+			So(h.checkCache(rec, httptest.NewRequest("GET", "/skin/x", nil), texture, false, ""), ShouldBeFalse)
+			So(rec.Header().Get("Last-Modified"), ShouldEqual, "Fri, 14 Jul 2017 02:40:00 GMT")
+		})
+
+		Convey("Max ages should follow the texture source", func() {
+			h := New(mcTest)
+			h.MaxAge = time.Minute
+
+			So(h.maxAge(minecraft.Texture{Source: "UsernameAPI", URL: "http://skins.example.net/skins/clone1018.png"}, false), ShouldEqual, time.Minute)
+			So(h.maxAge(minecraft.Texture{Source: "SessionProfile", URL: "http://textures.minecraft.net/texture/abc123"}, false), ShouldEqual, DefaultContentAddressedMaxAge)
+			So(h.maxAge(minecraft.Texture{Source: "SessionProfile", URL: "http://textures.minecraft.net/texture/abc123"}, true), ShouldEqual, DefaultFallbackMaxAge)
+		})
+
+	})
+
+}
@@ -0,0 +1,321 @@
+// Package handler serves Minotar style avatars, renders and textures for
+// Minecraft players over HTTP
+package handler
+
+import (
+	"bytes"
+	"image"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/minotar/minecraft"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DefaultSize is the size of avatars when none is given
+	DefaultSize = 180
+	// MinSize is the smallest avatar we will render
+	MinSize = 8
+	// MaxSize is the largest avatar we will render
+	MaxSize = 300
+)
+
+// Handler is an http.Handler serving the following routes, where {player} is
+// a Username or UUID (optionally ending in ".png") and {size} is optional:
+//
+//	/avatar/{player}/{size} - the front of the head
+//	/helm/{player}/{size}   - the front of the head with the hat
+//	/body/{player}/{size}   - the front of the player, size wide and twice as high
+//	/skin/{player}          - the skin texture
+//	/cape/{player}          - the cape texture
+//	/profile/{player}       - the player's profile as JSON
+//	/profiles               - POST a JSON array of players to get their profiles
+//
+// Responses carry an ETag made from the texture hash (so If-None-Match gets
+// a 304), a Cache-Control max-age depending on where the texture came from and,
+// for textures from a SessionProfile, a Last-Modified of the textures property.
+//
+// Use http.StripPrefix to serve it below another path.
+type Handler struct {
+	Mc *minecraft.Minecraft
+	// Fallback returns the skin to use when a player's can't be fetched
+	// (eg. they have no account or the API is down). By default Steve or,
+	// for UUIDs the game would give it to, Alex (see DefaultSkin). Nil
+	// responds with a 404.
+	Fallback func(player string) (minecraft.Skin, error)
+
+	// FallbackMaxAge is how long responses using the Fallback may be cached
+	FallbackMaxAge time.Duration
+	// MaxAge is how long other responses may be cached
+	MaxAge time.Duration
+	// ContentAddressedMaxAge is how long responses using textures named by
+	// their hash may be cached
+	ContentAddressedMaxAge time.Duration
+}
+
+// New returns a Handler fetching skins with mc and falling back to the
+// DefaultSkin
+func New(mc *minecraft.Minecraft) *Handler {
+	h := &Handler{
+		Mc:                     mc,
+		FallbackMaxAge:         DefaultFallbackMaxAge,
+		MaxAge:                 DefaultMaxAge,
+		ContentAddressedMaxAge: DefaultContentAddressedMaxAge,
+	}
+	h.Fallback = h.DefaultSkin
+	return h
+}
+
+// NewWithConfig returns a Handler with a Minecraft, caching and fallback
+// configured by config
+func NewWithConfig(config minecraft.Config) (*Handler, error) {
+	mc, err := config.NewMinecraft()
+	if err != nil {
+		return nil, err
+	}
+
+	h := New(mc)
+	switch config.FallbackSkin {
+	case "steve":
+		h.Fallback = func(player string) (minecraft.Skin, error) {
+			return minecraft.FetchSkinForSteve()
+		}
+	case "none":
+		h.Fallback = nil
+	}
+	if config.Cache.MaxAge != 0 {
+		h.MaxAge = time.Duration(config.Cache.MaxAge)
+	}
+	if config.Cache.FallbackMaxAge != 0 {
+		h.FallbackMaxAge = time.Duration(config.Cache.FallbackMaxAge)
+	}
+	if config.Cache.ContentAddressedMaxAge != 0 {
+		h.ContentAddressedMaxAge = time.Duration(config.Cache.ContentAddressedMaxAge)
+	}
+	return h, nil
+}
+
+// DefaultSkin returns the default skin the game gives the player (see
+// minecraft.DefaultSkinModel), or Steve for usernames as they have no UUID to
+// go by and when Alex can't be fetched
+func (h *Handler) DefaultSkin(player string) (minecraft.Skin, error) {
+	if minecraft.RegexUUID.MatchString(player) {
+		if skin, err := h.Mc.FetchDefaultSkin(player); err == nil {
+			return skin, nil
+		}
+	}
+	return minecraft.FetchSkinForSteve()
+}
+
+// request is a parsed route
+type request struct {
+	route  string
+	player string
+	size   int
+}
+
+// ServeHTTP routes the request
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, status, msg := parseRequest(r.URL.Path)
+	if status != http.StatusOK {
+		http.Error(w, msg, status)
+		return
+	}
+
+	allow, allowed := "GET, HEAD", r.Method == http.MethodGet || r.Method == http.MethodHead
+	if req.route == "profiles" {
+		allow, allowed = "POST", r.Method == http.MethodPost
+	}
+	if !allowed {
+		w.Header().Set("Allow", allow)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	switch req.route {
+	case "avatar", "helm":
+		h.serveHead(w, r, req)
+	case "body":
+		h.serveBody(w, r, req)
+	case "skin":
+		h.serveSkin(w, r, req)
+	case "cape":
+		h.serveCape(w, r, req)
+	case "profile":
+		h.serveProfile(w, r, req)
+	case "profiles":
+		h.serveProfiles(w, r)
+	}
+}
+
+// parseRequest splits the path into the route, player and size, returning
+// the HTTP status and message to respond with if it is no good
+func parseRequest(path string) (request, int, string) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	req := request{route: parts[0], size: DefaultSize}
+
+	maxParts := 3
+	switch req.route {
+	case "avatar", "helm", "body":
+	case "skin", "cape", "profile":
+		// Textures are served as they are, and profiles have no size
+		maxParts = 2
+	case "profiles":
+		// The players are in the body
+		if len(parts) != 1 {
+			return req, http.StatusNotFound, "not found"
+		}
+		return req, http.StatusOK, ""
+	default:
+		return req, http.StatusNotFound, "not found"
+	}
+	if len(parts) < 2 || len(parts) > maxParts {
+		return req, http.StatusNotFound, "not found"
+	}
+
+	req.player = strings.TrimSuffix(parts[1], ".png")
+	if !minecraft.RegexUsernameOrUUID.MatchString(req.player) {
+		return req, http.StatusBadRequest, "invalid player"
+	}
+
+	if len(parts) == 3 {
+		size, err := strconv.Atoi(strings.TrimSuffix(parts[2], ".png"))
+		if err != nil || size < MinSize || size > MaxSize {
+			return req, http.StatusBadRequest, "invalid size (must be between " + strconv.Itoa(MinSize) + " and " + strconv.Itoa(MaxSize) + ")"
+		}
+		req.size = size
+	}
+	return req, http.StatusOK, ""
+}
+
+// errNoSkin is returned by skin when the player's can't be fetched and there
+// is no Fallback
+var errNoSkin = errors.New("skin not found")
+
+// skin fetches the player's skin for the request, or the fallback (when the
+// bool is true)
+func (h *Handler) skin(r *http.Request, player string) (minecraft.Skin, bool, error) {
+	skin, err := h.Mc.FetchSkinPlayerContext(r.Context(), player)
+	if err == nil {
+		return skin, false, nil
+	}
+	if r.Context().Err() != nil {
+		// The client has gone, so there's no one to fall back for
+		return skin, false, r.Context().Err()
+	}
+	if h.Fallback == nil {
+		return skin, false, errNoSkin
+	}
+
+	skin, err = h.Fallback(player)
+	return skin, true, err
+}
+
+func (h *Handler) serveHead(w http.ResponseWriter, r *http.Request, req request) {
+	skin, fallback, err := h.skin(r, req.player)
+	if err != nil {
+		skinError(w, err)
+		return
+	}
+	if h.checkCache(w, r, skin.Texture, fallback, req.variant()) {
+		return
+	}
+
+	head, err := skin.RenderHead(1, req.route == "helm")
+	if err != nil {
+		serverError(w, "unable to render avatar")
+		return
+	}
+	writePNG(w, minecraft.ResizeNearest(head, req.size, req.size))
+}
+
+func (h *Handler) serveBody(w http.ResponseWriter, r *http.Request, req request) {
+	skin, fallback, err := h.skin(r, req.player)
+	if err != nil {
+		skinError(w, err)
+		return
+	}
+	if h.checkCache(w, r, skin.Texture, fallback, req.variant()) {
+		return
+	}
+
+	body, err := skin.RenderBody(1, true)
+	if err != nil {
+		serverError(w, "unable to render body")
+		return
+	}
+	writePNG(w, minecraft.ResizeNearest(body, req.size, req.size*2))
+}
+
+func (h *Handler) serveSkin(w http.ResponseWriter, r *http.Request, req request) {
+	skin, fallback, err := h.skin(r, req.player)
+	if err != nil {
+		skinError(w, err)
+		return
+	}
+	if h.checkCache(w, r, skin.Texture, fallback, req.variant()) {
+		return
+	}
+	writeTexture(w, skin.Texture)
+}
+
+func (h *Handler) serveCape(w http.ResponseWriter, r *http.Request, req request) {
+	cape, err := h.Mc.FetchCapePlayerContext(r.Context(), req.player)
+	if err != nil {
+		// There is no default cape
+		http.Error(w, "cape not found", http.StatusNotFound)
+		return
+	}
+	if h.checkCache(w, r, cape.Texture, false, req.variant()) {
+		return
+	}
+	writeTexture(w, cape.Texture)
+}
+
+// writeTexture writes the texture as it was served to us, or re-encoded when
+// we don't have the original (eg. Steve)
+func writeTexture(w http.ResponseWriter, texture minecraft.Texture) {
+	if texture.Raw == nil {
+		writePNG(w, texture.Image)
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Content-Length", strconv.Itoa(len(texture.Raw)))
+	texture.WriteRaw(w)
+}
+
+// writePNG encodes the image before writing anything, so encoding errors can
+// still be reported
+func writePNG(w http.ResponseWriter, img image.Image) {
+	buf := &bytes.Buffer{}
+	if err := minecraft.EncodePNG(buf, img, minecraft.PNGOptions{}); err != nil {
+		serverError(w, "unable to encode image")
+		return
+	}
+
+	w.Header().Set("Content-Type", "image/png")
+	w.Header().Set("Content-Length", strconv.Itoa(buf.Len()))
+	buf.WriteTo(w)
+}
+
+// skinError responds for a skin which couldn't be fetched
+func skinError(w http.ResponseWriter, err error) {
+	if err == errNoSkin {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	serverError(w, "unable to fetch skin")
+}
+
+// serverError responds with a 500, making sure it won't be cached
+func serverError(w http.ResponseWriter, msg string) {
+	header := w.Header()
+	header.Del("ETag")
+	header.Del("Last-Modified")
+	header.Set("Cache-Control", "no-store")
+	http.Error(w, msg, http.StatusInternalServerError)
+}
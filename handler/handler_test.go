@@ -0,0 +1,220 @@
+// handler_test.go
+package handler
+
+import (
+	"context"
+	"image"
+	"image/draw"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/minotar/minecraft"
+	"github.com/minotar/minecraft/mockminecraft"
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+var mcTest *minecraft.Minecraft
+
+func TestMain(m *testing.M) {
+	mux := mockminecraft.ReturnMux()
+	rt, shutdown := mockminecraft.Setup(mux)
+
+	mcTest = minecraft.NewMinecraft()
+	mcTest.Client = &http.Client{Transport: rt}
+
+	code := m.Run()
+	shutdown()
+	os.Exit(code)
+}
+
+// get performs the request against a new Handler
+func get(path string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	New(mcTest).ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+	return rec
+}
+
+// decodePNG returns the image in the response as NRGBA, or nil
+func decodePNG(rec *httptest.ResponseRecorder) *image.NRGBA {
+	img, err := png.Decode(rec.Body)
+	if err != nil {
+		return nil
+	}
+
+	nrgba := image.NewNRGBA(img.Bounds())
+	draw.Draw(nrgba, nrgba.Rect, img, img.Bounds().Min, draw.Src)
+	return nrgba
+}
+
+func TestHandler(t *testing.T) {
+
+	Convey("Test avatars", t, func() {
+
+		Convey("Avatars should be the default size", func() {
+			rec := get("/avatar/clone1018")
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(rec.Header().Get("Content-Type"), ShouldEqual, "image/png")
+			So(decodePNG(rec).Bounds(), ShouldResemble, image.Rect(0, 0, DefaultSize, DefaultSize))
+		})
+
+		Convey("Avatars should be the requested size", func() {
+			rec := get("/helm/d9135e08-2f22-44c8-9cb0-bee234155292/64.png")
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(decodePNG(rec).Bounds(), ShouldResemble, image.Rect(0, 0, 64, 64))
+		})
+
+		Convey("Avatars should match the skin", func() {
+			skin, _ := mcTest.FetchSkinPlayer("clone1018")
+			head, _ := skin.RenderHead(1, false)
+			rec := get("/avatar/clone1018/8")
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(decodePNG(rec).Pix, ShouldResemble, head.Pix)
+		})
+
+		Convey("Unknown players should fall back to Alex by their UUID", func() {
+			alex, _ := mcTest.FetchSkinForAlex()
+			body, _ := alex.RenderBody(1, true)
+			rec := get("/body/10000000000000000000000000000001/16")
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(decodePNG(rec).Pix, ShouldResemble, body.Pix)
+			So(rec.Header().Get("ETag"), ShouldStartWith, `"`+alex.Hash)
+		})
+
+		Convey("Bodies should be twice as high as wide", func() {
+			rec := get("/body/clone1018/100")
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(decodePNG(rec).Bounds(), ShouldResemble, image.Rect(0, 0, 100, 200))
+		})
+
+		Convey("Unknown players should fall back to Steve", func() {
+			steve, _ := minecraft.FetchSkinForSteve()
+			head, _ := steve.RenderHead(1, true)
+			rec := get("/helm/10000000000000000000000000000000/8")
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(decodePNG(rec).Pix, ShouldResemble, head.Pix)
+		})
+
+		Convey("Cancelled requests should stop fetching and not fall back", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			req := httptest.NewRequest("GET", "/avatar/clone1018/8", nil).WithContext(ctx)
+			rec := httptest.NewRecorder()
+			New(mcTest).ServeHTTP(rec, req)
+
+			So(rec.Code, ShouldEqual, http.StatusInternalServerError)
+		})
+
+	})
+
+	Convey("Test textures", t, func() {
+
+		Convey("Skins should be passed through unchanged", func() {
+			skin, _ := mcTest.FetchSkinPlayer("clone1018")
+			rec := get("/skin/clone1018.png")
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(rec.Body.Bytes(), ShouldResemble, skin.Raw)
+		})
+
+		Convey("Unknown players should get Steve's skin", func() {
+			rec := get("/skin/10000000000000000000000000000000")
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(decodePNG(rec).Bounds(), ShouldResemble, image.Rect(0, 0, 64, 32))
+		})
+
+		Convey("Capes should be served when present", func() {
+			rec := get("/cape/citricsquid")
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(decodePNG(rec), ShouldNotBeNil)
+		})
+
+		Convey("Missing capes should be not found", func() {
+			rec := get("/cape/lukegb")
+
+			So(rec.Code, ShouldEqual, http.StatusNotFound)
+		})
+
+	})
+
+	Convey("Test bad requests", t, func() {
+
+		Convey("Bad routes should be not found", func() {
+			So(get("/").Code, ShouldEqual, http.StatusNotFound)
+			So(get("/head/clone1018").Code, ShouldEqual, http.StatusNotFound)
+			So(get("/avatar").Code, ShouldEqual, http.StatusNotFound)
+			So(get("/skin/clone1018/100").Code, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Bad players and sizes should be rejected", func() {
+			So(get("/avatar/_-proscope-_").Code, ShouldEqual, http.StatusBadRequest)
+			So(get("/avatar/clone1018/big").Code, ShouldEqual, http.StatusBadRequest)
+			So(get("/avatar/clone1018/4").Code, ShouldEqual, http.StatusBadRequest)
+			So(get("/avatar/clone1018/301").Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Only GET and HEAD should be allowed", func() {
+			rec := httptest.NewRecorder()
+			New(mcTest).ServeHTTP(rec, httptest.NewRequest("POST", "/avatar/clone1018", nil))
+
+			So(rec.Code, ShouldEqual, http.StatusMethodNotAllowed)
+			So(rec.Header().Get("Allow"), ShouldEqual, "GET, HEAD")
+		})
+
+	})
+
+	Convey("Test NewWithConfig", t, func() {
+
+		config := minecraft.Config{
+			Cache:        minecraft.CacheConfig{ContentAddressedMaxAge: minecraft.Duration(2 * time.Hour)},
+			FallbackSkin: "none",
+		}
+		h, err := NewWithConfig(config)
+		So(err, ShouldBeNil)
+		h.Mc.Client = mcTest.Client
+
+		serve := func(path string) *httptest.ResponseRecorder {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest("GET", path, nil))
+			return rec
+		}
+
+		Convey("The cache settings should be used", func() {
+			rec := serve("/skin/clone1018")
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(rec.Header().Get("Cache-Control"), ShouldEqual, "public, max-age=7200")
+		})
+
+		Convey("The steve fallback should always be Steve", func() {
+			h, _ := NewWithConfig(minecraft.Config{FallbackSkin: "steve"})
+			skin, err := h.Fallback("10000000000000000000000000000001")
+
+			So(err, ShouldBeNil)
+			So(skin.Hash, ShouldEqual, minecraft.SteveHash)
+		})
+
+		Convey("Without a fallback missing skins should be not found", func() {
+			So(serve("/avatar/10000000000000000000000000000000").Code, ShouldEqual, http.StatusNotFound)
+			So(serve("/skin/10000000000000000000000000000000").Code, ShouldEqual, http.StatusNotFound)
+		})
+
+		Convey("Bad configs should fail", func() {
+			_, err := NewWithConfig(minecraft.Config{Preset: "minotar"})
+			So(err, ShouldNotBeNil)
+		})
+
+	})
+
+}
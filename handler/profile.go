@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/minotar/minecraft"
+	"github.com/pkg/errors"
+)
+
+const (
+	// MaxBatch is the most players that can be asked for in one /profiles
+	// request (each takes up to four upstream requests)
+	MaxBatch = 20
+	// BatchWorkers is how many players of a /profiles request are fetched at
+	// once
+	BatchWorkers = 4
+)
+
+// ProfileResponse is the JSON returned for a player
+type ProfileResponse struct {
+	UUID       string `json:"uuid"`
+	UUIDDashed string `json:"uuid_dashed"`
+	Username   string `json:"username"`
+	// Timestamp of the textures property, when known
+	Timestamp *time.Time       `json:"timestamp,omitempty"`
+	Skin      *TextureResponse `json:"skin"`
+	Cape      *TextureResponse `json:"cape"`
+	// Stale is true when the profile was served from the cache past its TTL
+	Stale bool `json:"stale,omitempty"`
+}
+
+// TextureResponse is the JSON for a skin or cape
+type TextureResponse struct {
+	URL        string `json:"url"`
+	Model      string `json:"model,omitempty"`
+	Hash       string `json:"hash"`
+	HashSHA256 string `json:"hash_sha256"`
+	Source     string `json:"source"`
+}
+
+// BatchResponse is the JSON returned for each player asked for in /profiles
+type BatchResponse struct {
+	Player  string           `json:"player"`
+	Profile *ProfileResponse `json:"profile,omitempty"`
+	Error   string           `json:"error,omitempty"`
+}
+
+// errorResponse is the JSON returned when a request fails
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewProfileResponse converts the profile for JSON
+func NewProfileResponse(profile minecraft.Profile) *ProfileResponse {
+	dashed, _ := minecraft.DashUUID(profile.UUID)
+	resp := &ProfileResponse{
+		UUID:       profile.UUID,
+		UUIDDashed: dashed,
+		Username:   profile.Username,
+		Stale:      profile.Stale,
+	}
+	if !profile.Timestamp.IsZero() {
+		timestamp := profile.Timestamp.UTC()
+		resp.Timestamp = &timestamp
+	}
+	if profile.Skin != nil {
+		resp.Skin = newTextureResponse(profile.Skin.Texture)
+	}
+	if profile.Cape != nil {
+		resp.Cape = newTextureResponse(profile.Cape.Texture)
+	}
+	return resp
+}
+
+func newTextureResponse(texture minecraft.Texture) *TextureResponse {
+	return &TextureResponse{
+		URL:        texture.URL,
+		Model:      texture.Model,
+		Hash:       texture.Hash,
+		HashSHA256: texture.HashSHA256,
+		Source:     texture.Source,
+	}
+}
+
+// profileError picks the status and message for a failed profile lookup
+func profileError(err error) (int, string) {
+	switch errors.Cause(err) {
+	case minecraft.ErrUserNotFound:
+		return http.StatusNotFound, "player not found"
+	case minecraft.ErrRateLimited:
+		return http.StatusServiceUnavailable, "rate limited"
+	}
+	return http.StatusBadGateway, "unable to fetch profile"
+}
+
+func (h *Handler) serveProfile(w http.ResponseWriter, r *http.Request, req request) {
+	profile, err := h.Mc.FetchProfileContext(r.Context(), req.player)
+	if err != nil {
+		status, msg := profileError(err)
+		writeJSON(w, status, errorResponse{msg})
+		return
+	}
+	writeJSON(w, http.StatusOK, NewProfileResponse(profile))
+}
+
+func (h *Handler) serveProfiles(w http.ResponseWriter, r *http.Request) {
+	var players []string
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64*1024)).Decode(&players); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"expected a JSON array of players"})
+		return
+	}
+	if len(players) > MaxBatch {
+		writeJSON(w, http.StatusBadRequest, errorResponse{"too many players (at most " + strconv.Itoa(MaxBatch) + ")"})
+		return
+	}
+
+	// Players asked for more than once are only fetched once
+	lookups := make(map[string]int, len(players))
+	var unique []string
+	for _, player := range players {
+		key := playerKey(player)
+		if _, seen := lookups[key]; !seen {
+			lookups[key] = len(unique)
+			unique = append(unique, player)
+		}
+	}
+
+	results := make([]BatchResponse, len(unique))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for n := 0; n < BatchWorkers && n < len(unique); n++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = h.batchProfile(r, unique[i])
+			}
+		}()
+	}
+	for i := range unique {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	batch := make([]BatchResponse, len(players))
+	for i, player := range players {
+		batch[i] = results[lookups[playerKey(player)]]
+		batch[i].Player = player
+	}
+	writeJSON(w, http.StatusOK, batch)
+}
+
+// playerKey is the same for every way of writing a player: usernames are
+// case insensitive and UUIDs may be dashed or not
+func playerKey(player string) string {
+	return strings.ToLower(strings.Replace(player, "-", "", -1))
+}
+
+// batchProfile fetches a player of a /profiles request, giving up once the
+// request is cancelled
+func (h *Handler) batchProfile(r *http.Request, player string) BatchResponse {
+	resp := BatchResponse{Player: player}
+	if !minecraft.RegexUsernameOrUUID.MatchString(player) {
+		resp.Error = "invalid player"
+		return resp
+	}
+
+	profile, err := h.Mc.FetchProfileContext(r.Context(), player)
+	if err != nil {
+		_, resp.Error = profileError(err)
+		return resp
+	}
+	resp.Profile = NewProfileResponse(profile)
+	return resp
+}
+
+// writeJSON writes v as the JSON response
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		serverError(w, "unable to encode JSON")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)+1))
+	w.WriteHeader(status)
+	w.Write(append(body, '\n'))
+}
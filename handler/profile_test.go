@@ -0,0 +1,132 @@
+// profile_test.go
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// post performs the POST against a new Handler
+func post(path string, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	New(mcTest).ServeHTTP(rec, httptest.NewRequest("POST", path, strings.NewReader(body)))
+	return rec
+}
+
+func TestProfile(t *testing.T) {
+
+	Convey("Test /profile", t, func() {
+
+		Convey("citricsquid should have a skin and cape", func() {
+			rec := get("/profile/citricsquid")
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(rec.Header().Get("Content-Type"), ShouldEqual, "application/json")
+
+			var profile ProfileResponse
+			So(json.Unmarshal(rec.Body.Bytes(), &profile), ShouldBeNil)
+			So(profile, ShouldResemble, ProfileResponse{
+				UUID:       "48a0a7e4d5594873a617dc189f76a8a1",
+				UUIDDashed: "48a0a7e4-d559-4873-a617-dc189f76a8a1",
+				Username:   "citricsquid",
+				Skin: &TextureResponse{
+					URL:        "http://textures.minecraft.net/texture/e1c6c9b6de88f4188f9732909c76dfcd7b16a40a031ce1b4868e4d1f8898e4f",
+					Hash:       "c05454f331fa93b3e38866a9ec52c467",
+					HashSHA256: profile.Skin.HashSHA256,
+					Source:     "SessionProfile",
+				},
+				Cape: &TextureResponse{
+					URL:        "http://textures.minecraft.net/texture/c3af7fb821254664558f28361158ca73303c9a85e96e5251102958d7ed60c4a3",
+					Hash:       "8cbf8786caba2f05383cf887be592ee6",
+					HashSHA256: profile.Cape.HashSHA256,
+					Source:     "SessionProfile",
+				},
+			})
+			So(profile.Skin.HashSHA256, ShouldHaveLength, 64)
+		})
+
+		Convey("Players without a cape should have a null cape", func() {
+			rec := get("/profile/clone1018")
+
+			So(rec.Code, ShouldEqual, http.StatusOK)
+			So(rec.Body.String(), ShouldContainSubstring, `"cape":null`)
+		})
+
+		Convey("Failures should be JSON errors", func() {
+			rec := get("/profile/10000000000000000000000000000000")
+			So(rec.Code, ShouldEqual, http.StatusNotFound)
+			So(rec.Body.String(), ShouldEqual, `{"error":"player not found"}`+"\n")
+
+			rec = get("/profile/RateLimitAPI")
+			So(rec.Code, ShouldEqual, http.StatusServiceUnavailable)
+
+			rec = get("/profile/500API")
+			So(rec.Code, ShouldEqual, http.StatusBadGateway)
+		})
+
+	})
+
+	Convey("Test /profiles", t, func() {
+
+		Convey("Each player should get a profile or an error", func() {
+			rec := post("/profiles", `["clone1018", "48a0a7e4d5594873a617dc189f76a8a1", "10000000000000000000000000000000", "_-proscope-_"]`)
+			So(rec.Code, ShouldEqual, http.StatusOK)
+
+			var batch []BatchResponse
+			So(json.Unmarshal(rec.Body.Bytes(), &batch), ShouldBeNil)
+			So(batch, ShouldHaveLength, 4)
+			So(batch[0].Profile.Username, ShouldEqual, "clone1018")
+			So(batch[1].Profile.Username, ShouldEqual, "citricsquid")
+			So(batch[2], ShouldResemble, BatchResponse{Player: "10000000000000000000000000000000", Error: "player not found"})
+			So(batch[3], ShouldResemble, BatchResponse{Player: "_-proscope-_", Error: "invalid player"})
+		})
+
+		Convey("Bad batches should be rejected", func() {
+			So(post("/profiles", `{"player": "clone1018"}`).Code, ShouldEqual, http.StatusBadRequest)
+			So(post("/profiles", "["+strings.Repeat(`"clone1018",`, MaxBatch)+`"clone1018"]`).Code, ShouldEqual, http.StatusBadRequest)
+		})
+
+		Convey("Duplicate players should be fetched once and answered each time", func() {
+			rec := post("/profiles", `["clone1018", "48a0a7e4d5594873a617dc189f76a8a1", "Clone1018", "48a0a7e4-d559-4873-a617-dc189f76a8a1"]`)
+			So(rec.Code, ShouldEqual, http.StatusOK)
+
+			var batch []BatchResponse
+			So(json.Unmarshal(rec.Body.Bytes(), &batch), ShouldBeNil)
+			So(batch, ShouldHaveLength, 4)
+			So(batch[2].Player, ShouldEqual, "Clone1018")
+			So(batch[2].Profile, ShouldResemble, batch[0].Profile)
+			So(batch[3].Player, ShouldEqual, "48a0a7e4-d559-4873-a617-dc189f76a8a1")
+			So(batch[3].Profile, ShouldResemble, batch[1].Profile)
+			So(batch[1].Profile.Username, ShouldEqual, "citricsquid")
+		})
+
+		Convey("Cancelled requests should stop fetching", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			req := httptest.NewRequest("POST", "/profiles", strings.NewReader(`["clone1018", "citricsquid"]`)).WithContext(ctx)
+			rec := httptest.NewRecorder()
+			New(mcTest).ServeHTTP(rec, req)
+
+			var batch []BatchResponse
+			So(json.Unmarshal(rec.Body.Bytes(), &batch), ShouldBeNil)
+			So(batch, ShouldResemble, []BatchResponse{
+				{Player: "clone1018", Error: "unable to fetch profile"},
+				{Player: "citricsquid", Error: "unable to fetch profile"},
+			})
+		})
+
+		Convey("Only POST should be allowed", func() {
+			rec := get("/profiles")
+
+			So(rec.Code, ShouldEqual, http.StatusMethodNotAllowed)
+			So(rec.Header().Get("Allow"), ShouldEqual, "POST")
+		})
+
+	})
+
+}
@@ -2,8 +2,11 @@
 package minecraft
 
 import (
+	"context"
 	"io"
+	"log/slog"
 	"net/http"
+	"net/http/httptrace"
 	"regexp"
 	"time"
 
@@ -44,6 +47,14 @@ var (
 	RegexUsernameOrUUID = regexp.MustCompile("^" + ValidUsernameOrUUIDRegex + "$")
 )
 
+var (
+	// ErrUserNotFound is the cause of errors for players without an account
+	ErrUserNotFound = errors.New("user not found")
+
+	// ErrRateLimited is the cause of errors when the API has rate limited us
+	ErrRateLimited = errors.New("rate limited")
+)
+
 // UUIDAPI is the "recent" method for performing Mojang API requests using UUIDs
 type UUIDAPI struct {
 	// SessionServerURL is the address where we can append a UUID and get back a SessionProfileResponse (UUID, Username and Properties/Textures)
@@ -65,6 +76,19 @@ type Minecraft struct {
 	// Client allows the supply of a custom RoundTripper (among other things)
 	Client    *http.Client
 	UserAgent string
+	// MaxTextureBytes, if set, is the largest texture which will be fetched
+	MaxTextureBytes int64
+	// TexturePolicy, if set, is checked before fetching any texture (and
+	// each redirect)
+	TexturePolicy *URLPolicy
+	// Cache, if set, keeps responses for reuse
+	Cache *ResponseCache
+	// Observer, if set, is told about every request made
+	Observer Observer
+	// Logger, if set, logs each lookup, fetch and request
+	Logger *slog.Logger
+	// Tracer, if set, traces each lookup, fetch and request
+	Tracer Tracer
 	UUIDAPI
 	UsernameAPI
 }
@@ -91,30 +115,119 @@ func NewMinecraft() *Minecraft {
 // Mojang APIs have fairly standard responses and this makes those requests and
 // catches the errors. Remember to close the response!
 func (mc *Minecraft) apiRequest(url string) (io.ReadCloser, error) {
-	req, err := http.NewRequest("GET", url, nil)
+	return mc.apiRequestContext(context.Background(), url)
+}
+
+// apiRequestContext is apiRequest, tracing the request as a child of any
+// span in ctx
+func (mc *Minecraft) apiRequestContext(ctx context.Context, url string) (io.ReadCloser, error) {
+	resp, err := mc.apiResponseContext(ctx, url)
+	if resp == nil {
+		return nil, err
+	}
+	return resp.Body, err
+}
+
+// apiResponse is apiRequest, but returns the whole response for when the
+// headers are needed too. Remember to close the response body!
+func (mc *Minecraft) apiResponse(url string) (*http.Response, error) {
+	return mc.apiResponseContext(context.Background(), url)
+}
+
+// apiResponseContext is apiResponse, tracing the request as a child of any
+// span in ctx
+func (mc *Minecraft) apiResponseContext(ctx context.Context, url string) (*http.Response, error) {
+	return mc.kindResponse(ctx, mc.requestKind(url), url)
+}
+
+// textureResponse is apiResponseContext for a texture, checking the URL
+// against the TexturePolicy whatever it looks like (its host is checked when
+// connecting, so not for cached textures)
+func (mc *Minecraft) textureResponse(ctx context.Context, url string) (*http.Response, error) {
+	if mc.TexturePolicy != nil {
+		checked, err := mc.TexturePolicy.checkURL(url)
+		if err != nil {
+			return nil, err
+		}
+		url = checked.String()
+	}
+	return mc.kindResponse(ctx, KindTexture, url)
+}
+
+// kindResponse makes the request of the kind, using the Cache if there is one
+func (mc *Minecraft) kindResponse(ctx context.Context, kind string, url string) (*http.Response, error) {
+	if mc.Cache != nil {
+		return mc.cachedResponse(ctx, kind, url)
+	}
+	return mc.fetchResponse(ctx, kind, url)
+}
+
+// fetchResponse makes the request, telling the Tracer, Logger and Observer
+func (mc *Minecraft) fetchResponse(ctx context.Context, kind string, url string) (*http.Response, error) {
+	op := mc.startOperation(ctx, "minecraft.http", slog.String("kind", kind), slog.String("url", url))
+	if op.enabled() {
+		ctx = httptrace.WithClientTrace(op.ctx, op.clientTrace())
+	}
+
+	if mc.Observer == nil {
+		resp, err := mc.doRequest(ctx, kind, url)
+		op.endResponse(resp, err)
+		return resp, err
+	}
+
+	start := time.Now()
+	event := RequestEvent{Kind: kind, URL: url}
+	resp, err := mc.doRequest(ctx, kind, url)
+	op.endResponse(resp, err)
+	event.Err = err
+	if resp == nil {
+		event.Latency = time.Since(start)
+		mc.Observer.ObserveRequest(event)
+		return nil, err
+	}
+
+	// The Observer is told once the body has been read and closed
+	event.Status = resp.StatusCode
+	resp.Body = &observedBody{ReadCloser: resp.Body, observer: mc.Observer, event: event, start: start}
+	return resp, err
+}
+
+// doRequest makes the request for apiResponse
+func (mc *Minecraft) doRequest(ctx context.Context, kind string, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create request")
 	}
 
 	req.Header.Set("User-Agent", mc.UserAgent)
 
-	resp, err := mc.Client.Do(req)
+	client := mc.Client
+	if kind == KindTexture && mc.TexturePolicy != nil {
+		client = mc.TexturePolicy.client(client)
+	}
+
+	resp, err := client.Do(req)
 	if err != nil {
+		// So errors.Cause gives the reason a redirect or connection was refused
+		var policyErr *URLPolicyError
+		if errors.As(err, &policyErr) {
+			err = policyErr
+		}
 		return nil, errors.Wrap(err, "unable to GET URL")
 	}
 
 	switch resp.StatusCode {
 
 	case http.StatusOK:
-		return resp.Body, nil
+		return resp, nil
 
 	case http.StatusNoContent:
-		return resp.Body, errors.New("user not found")
+		return resp, ErrUserNotFound
 
 	case http.StatusTooManyRequests:
-		return resp.Body, errors.New("rate limited")
+		return resp, ErrRateLimited
 
 	default:
-		return resp.Body, errors.Errorf("apiRequest HTTP %s", resp.Status)
+		return resp, errors.Errorf("apiRequest HTTP %s", resp.Status)
 	}
 }
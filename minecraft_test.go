@@ -2,10 +2,16 @@
 package minecraft
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/minotar/minecraft/mockminecraft"
 	. "github.com/smartystreets/goconvey/convey"
@@ -124,3 +130,242 @@ func TestExtra(t *testing.T) {
 	})
 
 }
+
+func TestObserver(t *testing.T) {
+
+	Convey("Test Observer", t, func() {
+
+		var events []RequestEvent
+		mc := *mcTest
+		mc.Observer = ObserverFunc(func(event RequestEvent) {
+			events = append(events, event)
+		})
+
+		Convey("Every request should be observed", func() {
+			profile, err := mc.FetchProfile("citricsquid")
+			So(err, ShouldBeNil)
+
+			So(events, ShouldHaveLength, 4)
+			kinds := []string{KindProfile, KindSession, KindTexture, KindTexture}
+			for i, event := range events {
+				So(event.Kind, ShouldEqual, kinds[i])
+				So(event.Status, ShouldEqual, http.StatusOK)
+				So(event.Bytes, ShouldBeGreaterThan, 0)
+				So(event.Latency, ShouldBeGreaterThan, 0)
+				So(event.Err, ShouldBeNil)
+			}
+			So(events[2].URL, ShouldEqual, profile.Skin.URL)
+			So(events[2].Bytes, ShouldEqual, len(profile.Skin.Raw))
+		})
+
+		Convey("Errors should be observed", func() {
+			_, err := mc.GetAPIProfile("RateLimitAPI")
+			So(err, ShouldNotBeNil)
+
+			So(events, ShouldHaveLength, 1)
+			So(events[0].Status, ShouldEqual, http.StatusTooManyRequests)
+			So(events[0].Err, ShouldEqual, ErrRateLimited)
+
+			mc.apiRequest("::")
+			So(events, ShouldHaveLength, 2)
+			So(events[1].Kind, ShouldEqual, KindTexture)
+			So(events[1].Status, ShouldEqual, 0)
+			So(events[1].Err, ShouldNotBeNil)
+		})
+
+	})
+
+	Convey("Test PrometheusObserver", t, func() {
+
+		p := NewPrometheusObserverBuckets([]float64{0.1, 1})
+		p.ObserveRequest(RequestEvent{Kind: KindProfile, Status: 200, Latency: 50 * time.Millisecond, Bytes: 60})
+		p.ObserveRequest(RequestEvent{Kind: KindProfile, Status: 429, Latency: 500 * time.Millisecond, Bytes: 10})
+		p.ObserveRequest(RequestEvent{Kind: KindTexture, Latency: 2 * time.Second})
+		p.ObserveRequest(RequestEvent{Kind: KindTexture, Status: 200, CacheHit: true})
+
+		expected := `# HELP minecraft_api_requests_total Requests made to the APIs.
+# TYPE minecraft_api_requests_total counter
+minecraft_api_requests_total{kind="profile",code="200"} 1
+minecraft_api_requests_total{kind="profile",code="429"} 1
+minecraft_api_requests_total{kind="texture",code="200"} 1
+minecraft_api_requests_total{kind="texture",code="none"} 1
+# HELP minecraft_api_cache_total Requests answered from the cache (hit) or not (miss).
+# TYPE minecraft_api_cache_total counter
+minecraft_api_cache_total{kind="profile",result="miss"} 2
+minecraft_api_cache_total{kind="texture",result="hit"} 1
+minecraft_api_cache_total{kind="texture",result="miss"} 1
+# HELP minecraft_api_response_bytes_total Bytes read from API responses.
+# TYPE minecraft_api_response_bytes_total counter
+minecraft_api_response_bytes_total{kind="profile"} 70
+minecraft_api_response_bytes_total{kind="texture"} 0
+# HELP minecraft_api_request_duration_seconds Latency of requests to the APIs.
+# TYPE minecraft_api_request_duration_seconds histogram
+minecraft_api_request_duration_seconds_bucket{kind="profile",le="0.1"} 1
+minecraft_api_request_duration_seconds_bucket{kind="profile",le="1"} 2
+minecraft_api_request_duration_seconds_bucket{kind="profile",le="+Inf"} 2
+minecraft_api_request_duration_seconds_sum{kind="profile"} 0.55
+minecraft_api_request_duration_seconds_count{kind="profile"} 2
+minecraft_api_request_duration_seconds_bucket{kind="texture",le="0.1"} 1
+minecraft_api_request_duration_seconds_bucket{kind="texture",le="1"} 1
+minecraft_api_request_duration_seconds_bucket{kind="texture",le="+Inf"} 2
+minecraft_api_request_duration_seconds_sum{kind="texture"} 2
+minecraft_api_request_duration_seconds_count{kind="texture"} 2
+`
+
+		Convey("Metrics should be written in the text format", func() {
+			buf := &bytes.Buffer{}
+			_, err := p.WriteTo(buf)
+
+			So(err, ShouldBeNil)
+			So(buf.String(), ShouldEqual, expected)
+		})
+
+		Convey("Metrics should be served", func() {
+			rec := httptest.NewRecorder()
+			p.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+			So(rec.Header().Get("Content-Type"), ShouldStartWith, "text/plain; version=0.0.4")
+			So(rec.Body.String(), ShouldEqual, expected)
+		})
+
+	})
+
+}
+
+// testSpan records what a span was told
+type testSpan struct {
+	name   string
+	parent string
+	attrs  map[string]slog.Value
+	events []string
+	err    error
+	ended  bool
+}
+
+func (s *testSpan) SetAttributes(attrs ...slog.Attr) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *testSpan) AddEvent(name string, attrs ...slog.Attr) {
+	s.events = append(s.events, name)
+}
+
+func (s *testSpan) End(err error) {
+	s.err, s.ended = err, true
+}
+
+// testTracer records the spans started, in order
+type testTracer struct {
+	spans []*testSpan
+}
+
+type testSpanKey struct{}
+
+func (t *testTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	span := &testSpan{name: name, attrs: make(map[string]slog.Value)}
+	if parent, ok := ctx.Value(testSpanKey{}).(*testSpan); ok {
+		span.parent = parent.name
+	}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, testSpanKey{}, span), span
+}
+
+func TestTracing(t *testing.T) {
+
+	Convey("Test Tracer", t, func() {
+
+		tracer := &testTracer{}
+		mc := *mcTest
+		mc.Tracer = tracer
+
+		Convey("Lookups, fetches, decodes and requests should be traced", func() {
+			_, err := mc.FetchProfile("citricsquid")
+			So(err, ShouldBeNil)
+
+			var names []string
+			for _, span := range tracer.spans {
+				names = append(names, span.name+"<"+span.parent)
+				So(span.ended, ShouldBeTrue)
+				So(span.err, ShouldBeNil)
+			}
+			So(names, ShouldResemble, []string{
+				"minecraft.profile<",
+				"minecraft.http<minecraft.profile",
+				"minecraft.session<",
+				"minecraft.http<minecraft.session",
+				"minecraft.texture<",
+				"minecraft.http<minecraft.texture",
+				"minecraft.texture.decode<minecraft.texture",
+				"minecraft.texture<",
+				"minecraft.http<minecraft.texture",
+				"minecraft.texture.decode<minecraft.texture",
+			})
+
+			httpSpan := tracer.spans[1]
+			So(httpSpan.attrs["kind"].String(), ShouldEqual, KindProfile)
+			So(httpSpan.attrs["status"].Int64(), ShouldEqual, 200)
+			So(httpSpan.attrs["first_byte"].Duration(), ShouldBeGreaterThan, 0)
+			So(httpSpan.events, ShouldContain, "first_byte")
+			So(tracer.spans[0].attrs["username"].String(), ShouldEqual, "citricsquid")
+		})
+
+		Convey("Failures should end the span with the error", func() {
+			_, err := mc.GetAPIProfile("RateLimitAPI")
+			So(err, ShouldNotBeNil)
+
+			So(tracer.spans, ShouldHaveLength, 2)
+			So(tracer.spans[0].err, ShouldEqual, err)
+			So(tracer.spans[1].err, ShouldEqual, ErrRateLimited)
+			So(tracer.spans[1].attrs["status"].Int64(), ShouldEqual, http.StatusTooManyRequests)
+		})
+
+	})
+
+	Convey("Test Logger", t, func() {
+
+		buf := &bytes.Buffer{}
+		mc := *mcTest
+		mc.Logger = slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+
+		logs := func() []map[string]interface{} {
+			var lines []map[string]interface{}
+			decoder := json.NewDecoder(buf)
+			for decoder.More() {
+				var line map[string]interface{}
+				So(decoder.Decode(&line), ShouldBeNil)
+				lines = append(lines, line)
+			}
+			return lines
+		}
+
+		Convey("Operations should be logged when they end", func() {
+			_, err := mc.GetSessionProfile("48a0a7e4d5594873a617dc189f76a8a1")
+			So(err, ShouldBeNil)
+
+			lines := logs()
+			So(lines, ShouldHaveLength, 2)
+			So(lines[0]["msg"], ShouldEqual, "minecraft.http")
+			So(lines[0]["level"], ShouldEqual, "DEBUG")
+			So(lines[0]["kind"], ShouldEqual, KindSession)
+			So(lines[0]["status"], ShouldEqual, 200)
+			So(lines[0], ShouldContainKey, "first_byte")
+			So(lines[1]["msg"], ShouldEqual, "minecraft.session")
+			So(lines[1]["uuid"], ShouldEqual, "48a0a7e4d5594873a617dc189f76a8a1")
+			So(lines[1], ShouldContainKey, "duration")
+		})
+
+		Convey("Failures should be logged as warnings", func() {
+			_, err := mc.GetAPIProfile("skmkj88200aklk")
+			So(err, ShouldNotBeNil)
+
+			lines := logs()
+			So(lines, ShouldHaveLength, 2)
+			So(lines[1]["level"], ShouldEqual, "WARN")
+			So(lines[1]["error"], ShouldEqual, err.Error())
+		})
+
+	})
+
+}
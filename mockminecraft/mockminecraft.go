@@ -113,6 +113,8 @@ func CreateMaps() {
 		"/texture/e1c6c9b6de88f4188f9732909c76dfcd7b16a40a031ce1b4868e4d1f8898e4f": `iVBORw0KGgoAAAANSUhEUgAAAEAAAAAgCAIAAAAt/+nTAAAFJklEQVR42s2Y2W7UQBBF823kAYkdkh8ghH1JQsB2ZvH8KDwgsUPY9204VccyEWKMDGSYkdXqbld33aq6Vd2epaUZv09bW19v3JhOJm+vXPl8/TrPx83NN5cv80xHI1qGHzY2poMBktO6Xlq0H7gBN93ZAR9Yp+Pxl+3tmKyqaIfDbzdvYp6WYNLCGTAtS0C/u3oVoAERY4oCe4AObjpGBnuMz0JGoKqCJMNhtKMRHfxNHIJFVcUMwUHMmYUz4P21a+HvspTrEYTJJGhT10wG74uCIb6XRYtHoUyAcHaSBy5hkmEJM8bj4FJZBouGw0WkEIjBjQHBH0IxGllzojTt7OByBWgtTYsXgboOT6ebAyukHwwMiJlgNhOTYFdV/T+gWR9BGa61qEsMEAPRDLZoMpM1J9IjfR9iFCseaEZu0FYVu7kJdiK57waADCg8Tb1PlzOJ7hhCnroOoEVhuYwgiLiqAijJQL8sPTfMk+aMyyo8jwj8lI6634ITLeU/5wlRDD0QshaRGGAFccSkqswHhkG5PCuQ3H8DIEZdh/8AhCXjcUNxhnXt6Ys7m3I0GGBJvNXxyBcFAobRUABdq+gjue8GAK6tJ3KG1iAE6KIQja618Id3CUL6uFmVFKLT3KDKkm3ndMAFFE7Z9Fz0k98A/VFMcXYmdCM/GoEMM4Ao42WXLpdyplD0y3IeJy66w21ZZCQP4NoLBa/wazvTJHpeKwwUSyIHknvNGZdHOKvmksS/I3EQaTzW5d4swkgplKWpOROynkowZPRLG8l5nQkEwQMBF2bfyth9WofXIaFMa2tUspGtwuzJRMkOFvyyZPc2wLPMgtOeVh00kDbGEN2xPGsuJhkllwf6rLldV/e0vFmYNfpPDPiJvgxF1mVwVZnBfvR4VDeZQEDyruEh3VGRwlllSRvoJ5OmMP7BV154Is/XQJYfAF4uOu5LFlB0ew4Kojk3SJKWWsmNjlLu10VzT8kq0ta93/yenjnz8sKF15cuofjuoUMmpZ0X58+Dg7cM/S6joxosfHb2LGp219e9ODw+fZoHzxkQ5lkOGl69ungRGRSx1hKMTF+9Xby/d/jwk5Mn0Sq+J2trdJ6fO/fo+PGHx44BgqE3M3dEk6UTZAAFBChZbugienXNKyQxibdsCCYPdWSYV7KX3tkROHUKZSB4cPQocjhGixniY17dXl5miBrQ0AEQaGjRRGuOgoahn5d0eEDDgxcVYx/esrn1msm+emcacOvAAUPJjntDiW8M5Z2DB/eGkkl2RAevROm9GhtYyD4SicjgVIQNhW/BDWJd0FfvP6PQ3/ueJbz9ZxRCsaFUrg0lQ0OJL/eGEsV4CHkkveF56PBWS2g1DDEwmbW0Hs/M0EGmr96ZBiDt3csI0gcWHZzESibdiNaN0GT9wWf0rfrC8rpBB/TeOJCxwmqn3OBhh756uyhE3sBI3IYc+xpKlN0/coRMUoGMl0JAAbrFB1exBGHtke66Eyj+IclCcBgTELOVxaqX3pkGQEQ9ZNbrSzoQ0fg+PnHCyLK1ZAUfYrQG2rwUtAmAblprpUGw8iLm7Qjb+uqdaQByXoN3V1akuKajwLRTgVVfA0J4fd0bP8rAwaS3a+axpK05tFYkVXgIWqD66p1pQHBgdZWapaF+VQmUEPO4LyG2XOA8SS9btAR9Ega++op9/O/I/7R9a24401dv178SFm98qSd4JB+P/35KfRWwo1VFx6PeosS8wiz3cLDee3I7lHtAR11fvbPwfwejl0CqnPd41wAAAABJRU5ErkJggg==`,
 		// citricquid cape
 		"/texture/c3af7fb821254664558f28361158ca73303c9a85e96e5251102958d7ed60c4a3": `iVBORw0KGgoAAAANSUhEUgAAAEAAAAAgCAMAAACVQ462AAAACXBIWXMAAA7DAAAOwwHHb6hkAAAKT2lDQ1BQaG90b3Nob3AgSUNDIHByb2ZpbGUAAHjanVNnVFPpFj333vRCS4iAlEtvUhUIIFJCi4AUkSYqIQkQSoghodkVUcERRUUEG8igiAOOjoCMFVEsDIoK2AfkIaKOg6OIisr74Xuja9a89+bN/rXXPues852zzwfACAyWSDNRNYAMqUIeEeCDx8TG4eQuQIEKJHAAEAizZCFz/SMBAPh+PDwrIsAHvgABeNMLCADATZvAMByH/w/qQplcAYCEAcB0kThLCIAUAEB6jkKmAEBGAYCdmCZTAKAEAGDLY2LjAFAtAGAnf+bTAICd+Jl7AQBblCEVAaCRACATZYhEAGg7AKzPVopFAFgwABRmS8Q5ANgtADBJV2ZIALC3AMDOEAuyAAgMADBRiIUpAAR7AGDIIyN4AISZABRG8lc88SuuEOcqAAB4mbI8uSQ5RYFbCC1xB1dXLh4ozkkXKxQ2YQJhmkAuwnmZGTKBNA/g88wAAKCRFRHgg/P9eM4Ors7ONo62Dl8t6r8G/yJiYuP+5c+rcEAAAOF0ftH+LC+zGoA7BoBt/qIl7gRoXgugdfeLZrIPQLUAoOnaV/Nw+H48PEWhkLnZ2eXk5NhKxEJbYcpXff5nwl/AV/1s+X48/Pf14L7iJIEyXYFHBPjgwsz0TKUcz5IJhGLc5o9H/LcL//wd0yLESWK5WCoU41EScY5EmozzMqUiiUKSKcUl0v9k4t8s+wM+3zUAsGo+AXuRLahdYwP2SycQWHTA4vcAAPK7b8HUKAgDgGiD4c93/+8//UegJQCAZkmScQAAXkQkLlTKsz/HCAAARKCBKrBBG/TBGCzABhzBBdzBC/xgNoRCJMTCQhBCCmSAHHJgKayCQiiGzbAdKmAv1EAdNMBRaIaTcA4uwlW4Dj1wD/phCJ7BKLyBCQRByAgTYSHaiAFiilgjjggXmYX4IcFIBBKLJCDJiBRRIkuRNUgxUopUIFVIHfI9cgI5h1xGupE7yAAygvyGvEcxlIGyUT3UDLVDuag3GoRGogvQZHQxmo8WoJvQcrQaPYw2oefQq2gP2o8+Q8cwwOgYBzPEbDAuxsNCsTgsCZNjy7EirAyrxhqwVqwDu4n1Y8+xdwQSgUXACTYEd0IgYR5BSFhMWE7YSKggHCQ0EdoJNwkDhFHCJyKTqEu0JroR+cQYYjIxh1hILCPWEo8TLxB7iEPENyQSiUMyJ7mQAkmxpFTSEtJG0m5SI+ksqZs0SBojk8naZGuyBzmULCAryIXkneTD5DPkG+Qh8lsKnWJAcaT4U+IoUspqShnlEOU05QZlmDJBVaOaUt2ooVQRNY9aQq2htlKvUYeoEzR1mjnNgxZJS6WtopXTGmgXaPdpr+h0uhHdlR5Ol9BX0svpR+iX6AP0dwwNhhWDx4hnKBmbGAcYZxl3GK+YTKYZ04sZx1QwNzHrmOeZD5lvVVgqtip8FZHKCpVKlSaVGyovVKmqpqreqgtV81XLVI+pXlN9rkZVM1PjqQnUlqtVqp1Q61MbU2epO6iHqmeob1Q/pH5Z/YkGWcNMw09DpFGgsV/jvMYgC2MZs3gsIWsNq4Z1gTXEJrHN2Xx2KruY/R27iz2qqaE5QzNKM1ezUvOUZj8H45hx+Jx0TgnnKKeX836K3hTvKeIpG6Y0TLkxZVxrqpaXllirSKtRq0frvTau7aedpr1Fu1n7gQ5Bx0onXCdHZ4/OBZ3nU9lT3acKpxZNPTr1ri6qa6UbobtEd79up+6Ynr5egJ5Mb6feeb3n+hx9L/1U/W36p/VHDFgGswwkBtsMzhg8xTVxbzwdL8fb8VFDXcNAQ6VhlWGX4YSRudE8o9VGjUYPjGnGXOMk423GbcajJgYmISZLTepN7ppSTbmmKaY7TDtMx83MzaLN1pk1mz0x1zLnm+eb15vft2BaeFostqi2uGVJsuRaplnutrxuhVo5WaVYVVpds0atna0l1rutu6cRp7lOk06rntZnw7Dxtsm2qbcZsOXYBtuutm22fWFnYhdnt8Wuw+6TvZN9un2N/T0HDYfZDqsdWh1+c7RyFDpWOt6azpzuP33F9JbpL2dYzxDP2DPjthPLKcRpnVOb00dnF2e5c4PziIuJS4LLLpc+Lpsbxt3IveRKdPVxXeF60vWdm7Obwu2o26/uNu5p7ofcn8w0nymeWTNz0MPIQ+BR5dE/C5+VMGvfrH5PQ0+BZ7XnIy9jL5FXrdewt6V3qvdh7xc+9j5yn+M+4zw33jLeWV/MN8C3yLfLT8Nvnl+F30N/I/9k/3r/0QCngCUBZwOJgUGBWwL7+Hp8Ib+OPzrbZfay2e1BjKC5QRVBj4KtguXBrSFoyOyQrSH355jOkc5pDoVQfujW0Adh5mGLw34MJ4WHhVeGP45wiFga0TGXNXfR3ENz30T6RJZE3ptnMU85ry1KNSo+qi5qPNo3ujS6P8YuZlnM1VidWElsSxw5LiquNm5svt/87fOH4p3iC+N7F5gvyF1weaHOwvSFpxapLhIsOpZATIhOOJTwQRAqqBaMJfITdyWOCnnCHcJnIi/RNtGI2ENcKh5O8kgqTXqS7JG8NXkkxTOlLOW5hCepkLxMDUzdmzqeFpp2IG0yPTq9MYOSkZBxQqohTZO2Z+pn5mZ2y6xlhbL+xW6Lty8elQfJa7OQrAVZLQq2QqboVFoo1yoHsmdlV2a/zYnKOZarnivN7cyzytuQN5zvn//tEsIS4ZK2pYZLVy0dWOa9rGo5sjxxedsK4xUFK4ZWBqw8uIq2Km3VT6vtV5eufr0mek1rgV7ByoLBtQFr6wtVCuWFfevc1+1dT1gvWd+1YfqGnRs+FYmKrhTbF5cVf9go3HjlG4dvyr+Z3JS0qavEuWTPZtJm6ebeLZ5bDpaql+aXDm4N2dq0Dd9WtO319kXbL5fNKNu7g7ZDuaO/PLi8ZafJzs07P1SkVPRU+lQ27tLdtWHX+G7R7ht7vPY07NXbW7z3/T7JvttVAVVN1WbVZftJ+7P3P66Jqun4lvttXa1ObXHtxwPSA/0HIw6217nU1R3SPVRSj9Yr60cOxx++/p3vdy0NNg1VjZzG4iNwRHnk6fcJ3/ceDTradox7rOEH0x92HWcdL2pCmvKaRptTmvtbYlu6T8w+0dbq3nr8R9sfD5w0PFl5SvNUyWna6YLTk2fyz4ydlZ19fi753GDborZ752PO32oPb++6EHTh0kX/i+c7vDvOXPK4dPKy2+UTV7hXmq86X23qdOo8/pPTT8e7nLuarrlca7nuer21e2b36RueN87d9L158Rb/1tWeOT3dvfN6b/fF9/XfFt1+cif9zsu72Xcn7q28T7xf9EDtQdlD3YfVP1v+3Njv3H9qwHeg89HcR/cGhYPP/pH1jw9DBY+Zj8uGDYbrnjg+OTniP3L96fynQ89kzyaeF/6i/suuFxYvfvjV69fO0ZjRoZfyl5O/bXyl/erA6xmv28bCxh6+yXgzMV70VvvtwXfcdx3vo98PT+R8IH8o/2j5sfVT0Kf7kxmTk/8EA5jz/GMzLdsAAAAgY0hSTQAAeiUAAICDAAD5/wAAgOkAAHUwAADqYAAAOpgAABdvkl/FRgAAAwBQTFRFAAAA////AQ4cASNEAR05ARYrAW3NAV6xAUeJATZpATJhDo7v+bsVtoAabFg0////AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAx/my9gAAABB0Uk5T////////////////////AOAjXRkAAACgSURBVHja7NAxEgMhDEPRbMQaWdjc/7gpyDKTLpAmxf5er9DjoAAAEJ+z4/seoFESacQ5WwFEq7VWozYBByAJ8E2AAiB37QOu7KTeH5R1ICMich/IyN4zchfw6J7delxAWQVERhqdOM+zrANyMrKZ+zZgEyhbAAfAAZR1wJp9AmXxRGvNWmt0jPky0AZguwAvgBMoK8DxYzdwAzfwL8BrANcaD+7cNnX3AAAAAElFTkSuQmCC`,
+		// Stand-in for Alex (a 64x64 copy of clone1018, not Mojang's texture)
+		"/texture/3b60a1f6d562f52aaebbf1434f1de147933a3affe0e764fa49ea057536623cd3": `iVBORw0KGgoAAAANSUhEUgAAAEAAAABACAYAAACqaXHeAAAF/UlEQVR42uxbW2gcVRj+ZnYu2e5u113SUktD32wUpaUN1mrVYjWlFw211QdbrKJQWiJIlBJUaFCKF7D4YFGpFu8gFoQSCNIiiqWxmmC9PKQvgSD40Ng1bRI3e5uRM90TzsycuezuJJlt54PDnMt/Zvt//+WczDkVAeheJZvN1ixD2n7mLXQR4YFsNmur8565XM4mT/vCDNGPEFXEqhBtv7C5ndvHktG0BPCsyFr79cfvdpzLEtPUHuAWDsWyhmaG7xzg5M5STGxqAiQ/IcAmOZYM0idLEkrlMo48Zg+FuKpcnyGQy+VMhBASmlF5Ct3vXiBbXddrWePd5MKwTxCq/xCTy7MWJ+2Xtq1GOpPBmwO/o+eBdkiigIqm4e3vLprk4bIPYL0nVOBZge07snvDbP2dfZv1E/u3zraP7t1kkuftBsO+KxSt1rMmvHiLCl3XsfrOD7C4RYGaSBh10teaTpoSJS+B8nKG26qyYEmQVfzD/dtAsaw1g8+6H8ahjaehJBMQFMWokz5JlkHxWtdaGxFOSoZFeQCQrNtc41kuXSPimYcwUyjixa8GTcshfZ44sN2Qefaj01AVxRbjYbU6CwGADgAAZtfyxZkMDp8cNCny5fM78d+lcQDAoqVL0P3pDwCAt3auw6FvhtG3az0mJ64AAF7++hx4nsXzhIVOisLhR+/SVUWCKstQVRViuYRXTv3KXQ2sbetfga9uvwNFXcBNiTjGxnOoVHQUCiUAwM2tafR8/j0oju7dhEsT03ij/5fweAAAdLQlXScM/TXlNmzMP7jtXkwXipBEEToATdfRIkt4f+AsRsS823RMjVXchpHKytBTGgBAmBQhI2UzRi2Q5oLVsqahXNFQKJYB6BBjMYMACmUyzZ1XTF3xfDdVfrY+GdAqEDS0igYBgCCKAGDU5wLW5BwKDwAApWrxYqVSl6WdQNyeDQGg0lgO6GhL6gAwni9gSVytaTKZk9PMbtmuxQEAB7ZuxNV8AQCwSJVx/NtzAGDKAawycwWvnCL6VZQHN8LeGzgLWYoZhSq/riNjk3v6tnTdnkDJZkH6yJhfiE4KOintJEN/lFh4pGrlj8/8ZBSq/PDQvyZZlgS2z0sBMr4qoTrKs2O+k2A97g9LVmZxcbpgFKI4VZ60WZC5g38WAQBPrU8ZiniFhZvybN2vJ4hW5XmWJ+NsP0/GmgfIP/KJ+5cBgKE88Qoqw8qSfpYELw9glbcSau3z4wm2jVCtYDcmLAE03qnyvKRHFSV9ZM6G25VroXN+0tEL2qtxb91Q0XfTJ5Xz2rjFOtqSfU8e7MHM2AUsTyvwU2fL5VIFUM0cblm31KY8AAhFwSRrtKtP8p78hIC2pTGsWaHit9GS7b0A0KrLJkKJjGlprP7GP0LZkP37atE9BB7c040zX7zraWkiQ2Sd4pJiz9rlfOUZGV6bKMALBytMyrvkCZ6XOEHv7e01fSUhbV4f74tKKisbJbkyZpT9O9t0sregbVKoDCvH9rH9pJD5z23NGqWzs1Pv7++v++n5TZAoxnoA6xFsnde2Jh0ae4R5JwvROHUas8b66LT5OwP7bdHpTJI37oTYzNiFPjAY/eNnrH9kH1asWoMfTx6HdcyKW1bfh64duyAJLejq3I2JsmQ8jfaOXfZnKc7vr843xqvvuWdLF4aHh82f6OLx2Tap5/N5ADCepE1B23TcCRIvSw4dO+Z7Fei8FXMKngWd/uihVkdIvz6FEmJEQERAREBEQERAREBEQERAREBEQERAREBEQETAjYcgDkcDvV9Qx/m/cF15gO38/0YPgUbP/5uOgFoOSQP5vUZf0Oj9gsuJxtx8aqwS/hxQz/2CoM7/54WAIO4XWJUP6vx/3jygHvevVfl6zv/nhYAg7hfwrBvE+b8XGk6Cjd4vSK6M2WIctZ3/Cwu6EyQ7P6cDVac6ixHk7X1inqu0VYaXJOc9BIK4X+CkvBNqPf+fazR0vyCA8/+FzQGN3i8I4PxfCMVOkAW5XwAA50994jXdIMDpar7T/1cMFQGNrgJW8CztMd6QDv8PAPE6mb9lwd76AAAAAElFTkSuQmCC`,
 		// Malformed
 		"/texture/MalformedTexture": `iVBORw0KGgoAAAANSUhEUgAAAEAAAAAgCAIAAAAt/+nTAAAFJklEQVR42s2Y2W7UQBBF823kAYkdkh8ghH1JQsB2ZvH8KDwgsUPY9204VccyEWKMDGSYkdXqbld33aq6Vd2epaUZv09bW19v3JhOJm+vXPl8/TrPx83NN5cv80xHI1qGHzY2poMBktO6Xlq0H7gBN93ZAR9Yp+Pxl+3tmKyqaIfDbzdvYp6WYNLCGTAtS0C/u3oVoAERY4oCe4AObjpGBnuMz0JGoKqCJMNhtKMRHfxNHIJFVcUMwUHMmYUz4P21a+HvspTrEYTJJGhT10wG74uCIb6XRYtHoUyAcHaSBy5hkmEJM8bj4FJZBouGw0WkEIjBjQHBH0IxGllzojTt7OByBWgtTYsXgboOT6ebAyukHwwMiJlgNhOTYFdV`,
 		// Dud
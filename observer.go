@@ -0,0 +1,81 @@
+package minecraft
+
+import (
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// The kinds of request reported to an Observer
+const (
+	KindProfile = "profile"
+	KindSession = "session"
+	KindTexture = "texture"
+)
+
+// RequestEvent describes a request made to the APIs
+type RequestEvent struct {
+	// Kind is KindProfile, KindSession or KindTexture
+	Kind string
+	URL  string
+	// Status is the HTTP status code, or 0 when there was no response
+	Status int
+	// Latency is from sending the request until the body was closed
+	Latency time.Duration
+	// Bytes is how much of the body was read
+	Bytes int64
+	// CacheHit is true when the response came from a cache
+	CacheHit bool
+	// Err is the error returned for the request, if any
+	Err error
+}
+
+// Observer is told about every request made to the APIs (eg. for metrics)
+type Observer interface {
+	ObserveRequest(event RequestEvent)
+}
+
+// ObserverFunc allows the use of a function as an Observer
+type ObserverFunc func(event RequestEvent)
+
+// ObserveRequest calls f(event)
+func (f ObserverFunc) ObserveRequest(event RequestEvent) {
+	f(event)
+}
+
+// requestKind works out the kind of request from the URL
+func (mc *Minecraft) requestKind(url string) string {
+	switch {
+	case mc.UUIDAPI.ProfileURL != "" && strings.HasPrefix(url, mc.UUIDAPI.ProfileURL):
+		return KindProfile
+	case mc.UUIDAPI.SessionServerURL != "" && strings.HasPrefix(url, mc.UUIDAPI.SessionServerURL):
+		return KindSession
+	}
+	return KindTexture
+}
+
+// observedBody counts what is read from a response body, telling the Observer
+// once it is closed
+type observedBody struct {
+	io.ReadCloser
+	observer Observer
+	event    RequestEvent
+	start    time.Time
+	once     sync.Once
+}
+
+func (b *observedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.event.Bytes += int64(n)
+	return n, err
+}
+
+func (b *observedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() {
+		b.event.Latency = time.Since(b.start)
+		b.observer.ObserveRequest(b.event)
+	})
+	return err
+}
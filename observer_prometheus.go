@@ -0,0 +1,163 @@
+package minecraft
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// DefaultLatencyBuckets are the upper bounds (in seconds) of the latency
+// histogram kept by a PrometheusObserver
+var DefaultLatencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// PrometheusObserver is an Observer keeping metrics of the requests, which it
+// writes in the Prometheus text exposition format. It is also an http.Handler
+// so it can be served for scraping (eg. at /metrics).
+type PrometheusObserver struct {
+	buckets []float64
+
+	mu       sync.Mutex
+	requests map[[2]string]uint64 // by kind and status code
+	cache    map[[2]string]uint64 // by kind and hit or miss
+	bytes    map[string]uint64
+	latency  map[string]*histogram
+}
+
+// histogram counts observations into cumulative buckets
+type histogram struct {
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewPrometheusObserver returns a PrometheusObserver using the
+// DefaultLatencyBuckets
+func NewPrometheusObserver() *PrometheusObserver {
+	return NewPrometheusObserverBuckets(DefaultLatencyBuckets)
+}
+
+// NewPrometheusObserverBuckets returns a PrometheusObserver with the given
+// latency buckets (in seconds, ascending)
+func NewPrometheusObserverBuckets(buckets []float64) *PrometheusObserver {
+	return &PrometheusObserver{
+		buckets:  append([]float64(nil), buckets...),
+		requests: make(map[[2]string]uint64),
+		cache:    make(map[[2]string]uint64),
+		bytes:    make(map[string]uint64),
+		latency:  make(map[string]*histogram),
+	}
+}
+
+// ObserveRequest records the request
+func (p *PrometheusObserver) ObserveRequest(event RequestEvent) {
+	code := "none"
+	if event.Status != 0 {
+		code = strconv.Itoa(event.Status)
+	}
+	result := "miss"
+	if event.CacheHit {
+		result = "hit"
+	}
+	seconds := event.Latency.Seconds()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.requests[[2]string{event.Kind, code}]++
+	p.cache[[2]string{event.Kind, result}]++
+	p.bytes[event.Kind] += uint64(event.Bytes)
+
+	h := p.latency[event.Kind]
+	if h == nil {
+		h = &histogram{counts: make([]uint64, len(p.buckets))}
+		p.latency[event.Kind] = h
+	}
+	for i, bound := range p.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// WriteTo writes the metrics in the Prometheus text exposition format
+func (p *PrometheusObserver) WriteTo(w io.Writer) (int64, error) {
+	buf := &bytes.Buffer{}
+
+	p.mu.Lock()
+	writeHeader(buf, "minecraft_api_requests_total", "counter", "Requests made to the APIs.")
+	for _, key := range sortedPairs(p.requests) {
+		fmt.Fprintf(buf, "minecraft_api_requests_total{kind=%q,code=%q} %d\n", key[0], key[1], p.requests[key])
+	}
+
+	writeHeader(buf, "minecraft_api_cache_total", "counter", "Requests answered from the cache (hit) or not (miss).")
+	for _, key := range sortedPairs(p.cache) {
+		fmt.Fprintf(buf, "minecraft_api_cache_total{kind=%q,result=%q} %d\n", key[0], key[1], p.cache[key])
+	}
+
+	writeHeader(buf, "minecraft_api_response_bytes_total", "counter", "Bytes read from API responses.")
+	for _, kind := range sortedKinds(p.bytes) {
+		fmt.Fprintf(buf, "minecraft_api_response_bytes_total{kind=%q} %d\n", kind, p.bytes[kind])
+	}
+
+	writeHeader(buf, "minecraft_api_request_duration_seconds", "histogram", "Latency of requests to the APIs.")
+	kinds := make([]string, 0, len(p.latency))
+	for kind := range p.latency {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		h := p.latency[kind]
+		for i, bound := range p.buckets {
+			fmt.Fprintf(buf, "minecraft_api_request_duration_seconds_bucket{kind=%q,le=%q} %d\n", kind, formatFloat(bound), h.counts[i])
+		}
+		fmt.Fprintf(buf, "minecraft_api_request_duration_seconds_bucket{kind=%q,le=\"+Inf\"} %d\n", kind, h.count)
+		fmt.Fprintf(buf, "minecraft_api_request_duration_seconds_sum{kind=%q} %s\n", kind, formatFloat(h.sum))
+		fmt.Fprintf(buf, "minecraft_api_request_duration_seconds_count{kind=%q} %d\n", kind, h.count)
+	}
+	p.mu.Unlock()
+
+	return buf.WriteTo(w)
+}
+
+// ServeHTTP serves the metrics for scraping
+func (p *PrometheusObserver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p.WriteTo(w)
+}
+
+func writeHeader(buf *bytes.Buffer, name, kind, help string) {
+	fmt.Fprintf(buf, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+func sortedPairs(m map[[2]string]uint64) [][2]string {
+	keys := make([][2]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
+
+func sortedKinds(m map[string]uint64) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,400 @@
+package minecraft
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// URLPolicy restricts the URLs textures will be fetched from. Texture URLs
+// come from the textures property (or a third party server), so without one a
+// forged property could have us fetch internal URLs.
+type URLPolicy struct {
+	// Schemes allowed, http and https when empty
+	Schemes []string `json:"schemes"`
+	// Hosts allowed, any when empty. Those starting with "." allow any
+	// subdomain (eg. ".example.net").
+	Hosts []string `json:"hosts"`
+	// Ports allowed, any when empty
+	Ports []int `json:"ports"`
+	// BlockPrivate rejects hosts which are, or resolve to, loopback, private,
+	// link-local or unspecified addresses. Texture hosts are resolved when
+	// connecting to them, and the addresses connected to are checked (see
+	// Control), so cached textures are still served when DNS is down and the
+	// host can't resolve differently in between. When a proxy connects for
+	// us the host is resolved and checked before the request instead, and
+	// the proxy itself is trusted.
+	BlockPrivate bool `json:"block_private"`
+	// Resolver resolves hosts for BlockPrivate, net.DefaultResolver when nil
+	Resolver *net.Resolver `json:"-"`
+	// UpgradeHTTP changes http URLs to https before they are checked
+	UpgradeHTTP bool `json:"upgrade_http"`
+}
+
+// MojangTexturePolicy only allows Mojang's texture server
+var MojangTexturePolicy = URLPolicy{
+	Hosts:       []string{"textures.minecraft.net"},
+	UpgradeHTTP: true,
+}
+
+// Reasons a URLPolicy rejects a URL, as the Reason of a URLPolicyError. A
+// URLPolicyError gives its Reason as its Cause, so they can be told apart
+// with errors.Cause.
+var (
+	ErrInvalidURL            = errors.New("invalid URL")
+	ErrCredentialsNotAllowed = errors.New("credentials not allowed")
+	ErrSchemeNotAllowed      = errors.New("scheme not allowed")
+	ErrHostNotAllowed        = errors.New("host not allowed")
+	ErrPortNotAllowed        = errors.New("port not allowed")
+	// ErrPrivateAddress is for hosts which are, or resolve to, a private
+	// address
+	ErrPrivateAddress = errors.New("private address")
+	// ErrResolveFailed is for hosts which couldn't be resolved to check
+	// their addresses
+	ErrResolveFailed = errors.New("unable to resolve host")
+)
+
+// URLPolicyError is returned for a URL rejected by a URLPolicy
+type URLPolicyError struct {
+	URL string
+	// Reason is what was wrong with it (eg. ErrHostNotAllowed)
+	Reason error
+}
+
+func (e *URLPolicyError) Error() string {
+	return fmt.Sprintf("URL %q rejected: %s", e.URL, e.Reason)
+}
+
+// Cause returns the Reason
+func (e *URLPolicyError) Cause() error {
+	return e.Reason
+}
+
+// Unwrap returns the Reason
+func (e *URLPolicyError) Unwrap() error {
+	return e.Reason
+}
+
+// Check returns the URL to fetch (upgraded to https when UpgradeHTTP is set),
+// or a *URLPolicyError if it isn't allowed
+func (p *URLPolicy) Check(rawURL string) (string, error) {
+	u, err := p.checkURL(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if p.BlockPrivate {
+		if _, err := p.resolvePublic(context.Background(), hostname(u)); err != nil {
+			err.URL = rawURL
+			return "", err
+		}
+	}
+	return u.String(), nil
+}
+
+// checkURL is Check without resolving the host, which is left until
+// connecting to it (so a cached texture doesn't need DNS)
+func (p *URLPolicy) checkURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return nil, &URLPolicyError{rawURL, ErrInvalidURL}
+	}
+	if u.User != nil {
+		return nil, &URLPolicyError{rawURL, ErrCredentialsNotAllowed}
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	if p.UpgradeHTTP && u.Scheme == "http" {
+		u.Scheme = "https"
+		if u.Port() == "80" {
+			u.Host = u.Hostname()
+			if strings.Contains(u.Host, ":") {
+				u.Host = "[" + u.Host + "]"
+			}
+		}
+	}
+
+	schemes := p.Schemes
+	if len(schemes) == 0 {
+		schemes = []string{"http", "https"}
+	}
+	if !containsString(schemes, u.Scheme) {
+		return nil, &URLPolicyError{rawURL, ErrSchemeNotAllowed}
+	}
+
+	host := hostname(u)
+	if len(p.Hosts) > 0 && !p.allowsHost(host) {
+		return nil, &URLPolicyError{rawURL, ErrHostNotAllowed}
+	}
+
+	if len(p.Ports) > 0 {
+		port := 443
+		if u.Scheme == "http" {
+			port = 80
+		}
+		if u.Port() != "" {
+			port, _ = strconv.Atoi(u.Port())
+		}
+		if !containsInt(p.Ports, port) {
+			return nil, &URLPolicyError{rawURL, ErrPortNotAllowed}
+		}
+	}
+
+	if ip := net.ParseIP(host); ip != nil && p.BlockPrivate && isPrivateIP(ip) {
+		return nil, &URLPolicyError{rawURL, ErrPrivateAddress}
+	}
+
+	return u, nil
+}
+
+// client returns a copy of the client which checks redirects against the
+// policy too (following them to the checked URL, so http is still upgraded)
+// and, with BlockPrivate, refuses connections to private addresses
+func (p *URLPolicy) client(client *http.Client) *http.Client {
+	checked := *client
+	checked.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		checkedURL, err := p.checkURL(req.URL.String())
+		if err != nil {
+			return err
+		}
+		req.URL = checkedURL
+		if client.CheckRedirect != nil {
+			return client.CheckRedirect(req, via)
+		}
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	if p.BlockPrivate {
+		checked.Transport = p.guardTransport(client.Transport)
+	}
+	return &checked
+}
+
+// guardedTransports are the transports made by guardTransport, so their
+// connections are reused between requests
+var guardedTransports sync.Map
+
+type guardedKey struct {
+	policy    *URLPolicy
+	transport *http.Transport
+}
+
+// guardTransport returns a copy of the transport which refuses connections to
+// private addresses, whatever the host resolves to when dialing. Transports
+// which aren't an *http.Transport can't be told where to connect, so the host
+// is resolved and checked before each request instead.
+func (p *URLPolicy) guardTransport(rt http.RoundTripper) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	transport, ok := rt.(*http.Transport)
+	if !ok {
+		return &guardedRoundTripper{policy: p, rt: rt}
+	}
+
+	key := guardedKey{p, transport}
+	if guarded, ok := guardedTransports.Load(key); ok {
+		return guarded.(*http.Transport)
+	}
+
+	g := &guard{policy: p, dial: transport.DialContext}
+	if transport == http.DefaultTransport {
+		// Its dialer is the one the guard uses
+		g.dial = nil
+	}
+	guarded := transport.Clone()
+	guarded.DialContext = g.dialContext
+	if proxy := transport.Proxy; proxy != nil {
+		guarded.Proxy = func(req *http.Request) (*url.URL, error) {
+			return g.proxy(proxy, req)
+		}
+	}
+
+	actual, _ := guardedTransports.LoadOrStore(key, guarded)
+	return actual.(*http.Transport)
+}
+
+// guard dials for a guarded transport
+type guard struct {
+	policy *URLPolicy
+	// dial is the transport's own DialContext, nil for a net.Dialer
+	dial func(ctx context.Context, network, address string) (net.Conn, error)
+	// proxies are the addresses of the proxies requests have been sent
+	// through, which are dialed without being checked
+	proxies sync.Map
+}
+
+func (g *guard) dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	_, proxy := g.proxies.Load(address)
+	if g.dial == nil {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second, Resolver: g.policy.Resolver}
+		if !proxy {
+			dialer.Control = g.policy.Control
+		}
+		return dialer.DialContext(ctx, network, address)
+	}
+	if proxy {
+		return g.dial(ctx, network, address)
+	}
+
+	// The transport's own dial can't be given a Control, so it's given the
+	// checked addresses to connect to instead
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to dial")
+	}
+	ips, policyErr := g.policy.resolvePublic(ctx, host)
+	if policyErr != nil {
+		policyErr.URL = address
+		return nil, policyErr
+	}
+	for _, ip := range ips {
+		var conn net.Conn
+		conn, err = g.dial(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+// proxy is the transport's Proxy, checking the host of requests sent through
+// a proxy (as it's the proxy which resolves and connects to it)
+func (g *guard) proxy(proxy func(*http.Request) (*url.URL, error), req *http.Request) (*url.URL, error) {
+	proxyURL, err := proxy(req)
+	if err != nil {
+		return nil, err
+	}
+	if proxyURL == nil {
+		if _, ok := g.proxies.Load(canonicalAddr(req.URL)); !ok {
+			return nil, nil
+		}
+		// Otherwise the dial would be taken to be to the proxy
+	}
+
+	if _, err := g.policy.resolvePublic(req.Context(), hostname(req.URL)); err != nil {
+		err.URL = req.URL.String()
+		return nil, err
+	}
+	if proxyURL != nil {
+		g.proxies.Store(canonicalAddr(proxyURL), true)
+	}
+	return proxyURL, nil
+}
+
+// guardedRoundTripper checks the host of each request before passing it on
+type guardedRoundTripper struct {
+	policy *URLPolicy
+	rt     http.RoundTripper
+}
+
+func (g *guardedRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if _, err := g.policy.resolvePublic(req.Context(), hostname(req.URL)); err != nil {
+		err.URL = req.URL.String()
+		return nil, err
+	}
+	return g.rt.RoundTrip(req)
+}
+
+func (p *URLPolicy) allowsHost(host string) bool {
+	for _, allowed := range p.Hosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || (strings.HasPrefix(allowed, ".") && strings.HasSuffix(host, allowed)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Control can be used as the Control of a net.Dialer to refuse connections
+// to private addresses, whatever the host resolved to
+func (p *URLPolicy) Control(network, address string, c syscall.RawConn) error {
+	if !p.BlockPrivate {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	if ip := net.ParseIP(host); ip == nil || isPrivateIP(ip) {
+		return &URLPolicyError{address, ErrPrivateAddress}
+	}
+	return nil
+}
+
+// resolvePublic returns the addresses of the host, or an error if it is or
+// resolves to a private address
+func (p *URLPolicy) resolvePublic(ctx context.Context, host string) ([]net.IP, *URLPolicyError) {
+	if ip := net.ParseIP(host); ip != nil {
+		if isPrivateIP(ip) {
+			return nil, &URLPolicyError{Reason: ErrPrivateAddress}
+		}
+		return []net.IP{ip}, nil
+	}
+
+	resolver := p.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	addrs, err := resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, &URLPolicyError{Reason: ErrResolveFailed}
+	}
+	ips := make([]net.IP, len(addrs))
+	for i, addr := range addrs {
+		if isPrivateIP(addr.IP) {
+			return nil, &URLPolicyError{Reason: ErrPrivateAddress}
+		}
+		ips[i] = addr.IP
+	}
+	return ips, nil
+}
+
+// hostname is the URL's host without a port or trailing dot, in lower case
+func hostname(u *url.URL) string {
+	return strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+}
+
+// canonicalAddr is the host:port the transport dials for the URL
+func canonicalAddr(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		switch u.Scheme {
+		case "https":
+			port = "443"
+		case "socks5":
+			port = "1080"
+		default:
+			port = "80"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
+
+// sharedAddressSpace is 100.64.0.0/10, used for carrier-grade NAT
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+func isPrivateIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() ||
+		sharedAddressSpace.Contains(ip)
+}
+
+func containsInt(list []int, i int) bool {
+	for _, item := range list {
+		if item == i {
+			return true
+		}
+	}
+	return false
+}
@@ -1,8 +1,11 @@
 package minecraft
 
 import (
+	"context"
 	"encoding/json"
+	"log/slog"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -16,11 +19,15 @@ type APIProfileResponse struct {
 	User
 	Legacy bool `json:"legacy"`
 	Demo   bool `json:"demo"`
+	// Stale is true when served from the Cache past its TTL
+	Stale bool `json:"-"`
 }
 
 type SessionProfileResponse struct {
 	User
 	Properties []SessionProfileProperty `json:"properties"`
+	// Stale is true when served from the Cache past its TTL
+	Stale bool `json:"-"`
 }
 
 type SessionProfileProperty struct {
@@ -32,23 +39,35 @@ type SessionProfileProperty struct {
 // for getting the UUID, but can also correct the capitilzation of a username or
 // possibly get the account status (legacy or demo) - only included when true
 func (mc *Minecraft) GetAPIProfile(username string) (APIProfileResponse, error) {
+	return mc.getAPIProfileContext(context.Background(), username)
+}
+
+func (mc *Minecraft) getAPIProfileContext(ctx context.Context, username string) (APIProfileResponse, error) {
+	op := mc.startOperation(ctx, "minecraft.profile", slog.String("username", username))
+	apiProfile, err := mc.getAPIProfile(op.ctx, username)
+	op.end(err)
+	return apiProfile, err
+}
+
+func (mc *Minecraft) getAPIProfile(ctx context.Context, username string) (APIProfileResponse, error) {
 	url := mc.UUIDAPI.ProfileURL
 	url += username
 
-	apiBody, err := mc.apiRequest(url)
-	if apiBody != nil {
-		defer apiBody.Close()
+	resp, err := mc.apiResponseContext(ctx, url)
+	if resp != nil {
+		defer resp.Body.Close()
 	}
 	if err != nil {
 		return APIProfileResponse{}, errors.Wrap(err, "unable to GetAPIProfile")
 	}
 
 	apiProfile := APIProfileResponse{}
-	err = json.NewDecoder(apiBody).Decode(&apiProfile)
+	err = json.NewDecoder(resp.Body).Decode(&apiProfile)
 	if err != nil {
 		return APIProfileResponse{}, errors.Wrap(err, "decoding GetAPIProfile failed")
 	}
 
+	apiProfile.Stale = isStale(resp)
 	return apiProfile, nil
 }
 
@@ -61,36 +80,130 @@ func (mc *Minecraft) GetUUID(username string) (string, error) {
 // NormalizePlayerForUUID takes either a Username or UUID and returns a UUID
 // formatted without dashes, or an error (eg. no account or an API error)
 func (mc *Minecraft) NormalizePlayerForUUID(player string) (string, error) {
+	uuid, _, err := mc.normalizePlayerForUUID(context.Background(), player)
+	return uuid, err
+}
+
+// normalizePlayerForUUID is NormalizePlayerForUUID, also returning whether the
+// username's API profile was served from the Cache past its TTL
+func (mc *Minecraft) normalizePlayerForUUID(ctx context.Context, player string) (string, bool, error) {
 	if RegexUsername.MatchString(player) {
-		return mc.GetUUID(player)
+		apiProfile, err := mc.getAPIProfileContext(ctx, player)
+		return apiProfile.UUID, apiProfile.Stale, err
 	} else if RegexUUID.MatchString(player) {
-		return strings.Replace(player, "-", "", 4), nil
+		return strings.Replace(player, "-", "", 4), false, nil
 	}
 
 	// We shouldn't get this far as there should have been Regex checks already.
-	return "", errors.New("unable to NormalizePlayerForUUID due to invalid Username/UUID")
+	return "", false, errors.New("unable to NormalizePlayerForUUID due to invalid Username/UUID")
 }
 
 // GetSessionProfile fetches the session profile of the UUID, this includes
 // extra properties for the user (currently just a textures property)
 // Rate limits if performing same request within 30 seconds
 func (mc *Minecraft) GetSessionProfile(uuid string) (SessionProfileResponse, error) {
+	return mc.getSessionProfileContext(context.Background(), uuid)
+}
+
+func (mc *Minecraft) getSessionProfileContext(ctx context.Context, uuid string) (SessionProfileResponse, error) {
+	op := mc.startOperation(ctx, "minecraft.session", slog.String("uuid", uuid))
+	sessionProfile, err := mc.getSessionProfile(op.ctx, uuid)
+	op.end(err)
+	return sessionProfile, err
+}
+
+func (mc *Minecraft) getSessionProfile(ctx context.Context, uuid string) (SessionProfileResponse, error) {
 	url := mc.UUIDAPI.SessionServerURL
 	url += uuid
 
-	apiBody, err := mc.apiRequest(url)
-	if apiBody != nil {
-		defer apiBody.Close()
+	resp, err := mc.apiResponseContext(ctx, url)
+	if resp != nil {
+		defer resp.Body.Close()
 	}
 	if err != nil {
 		return SessionProfileResponse{}, errors.Wrap(err, "unable to GetSessionProfile")
 	}
 
 	sessionProfile := SessionProfileResponse{}
-	err = json.NewDecoder(apiBody).Decode(&sessionProfile)
+	err = json.NewDecoder(resp.Body).Decode(&sessionProfile)
 	if err != nil {
 		return SessionProfileResponse{}, errors.Wrap(err, "decoding GetSessionProfile failed")
 	}
 
+	sessionProfile.Stale = isStale(resp)
 	return sessionProfile, nil
 }
+
+// DashUUID formats a UUID with dashes (eg. d9135e08-2f22-44c8-9cb0-bee234155292)
+func DashUUID(uuid string) (string, error) {
+	if RegexUUIDDash.MatchString(uuid) {
+		return uuid, nil
+	}
+	if !RegexUUIDPlain.MatchString(uuid) {
+		return "", errors.Errorf("unable to DashUUID: %q is not a UUID", uuid)
+	}
+	return uuid[0:8] + "-" + uuid[8:12] + "-" + uuid[12:16] + "-" + uuid[16:20] + "-" + uuid[20:32], nil
+}
+
+// Profile is everything we know of a player from their session profile
+type Profile struct {
+	User
+	// Timestamp of the textures property
+	Timestamp time.Time
+	// Skin is nil when the player uses the default skin
+	Skin *Skin
+	// Cape is nil when the player has no cape
+	Cape *Cape
+	// Stale is true when the API profile (looking up a username), session
+	// profile or textures were served from the Cache past their TTL
+	Stale bool
+}
+
+// FetchProfile takes a Username or UUID and fetches the player's session
+// profile along with their skin and cape
+func (mc *Minecraft) FetchProfile(player string) (Profile, error) {
+	return mc.FetchProfileContext(context.Background(), player)
+}
+
+// FetchProfileContext is FetchProfile, giving up on the requests once ctx is
+// done
+func (mc *Minecraft) FetchProfileContext(ctx context.Context, player string) (Profile, error) {
+	uuid, stale, err := mc.normalizePlayerForUUID(ctx, player)
+	if err != nil {
+		return Profile{}, errors.Wrap(err, "unable to FetchProfile")
+	}
+
+	sessionProfile, err := mc.getSessionProfileContext(ctx, uuid)
+	if err != nil {
+		return Profile{}, errors.Wrap(err, "unable to FetchProfile")
+	}
+	profile := Profile{User: sessionProfile.User, Stale: stale || sessionProfile.Stale}
+
+	profileTextureProperty, err := DecodeTextureProperty(sessionProfile)
+	if err != nil {
+		return profile, errors.Wrap(err, "unable to FetchProfile")
+	}
+	if profileTextureProperty.TimestampMs != 0 {
+		profile.Timestamp = profileTextureProperty.Time()
+	}
+
+	if profileTextureProperty.Textures.Skin.URL != "" {
+		skin := &Skin{Texture{Mc: mc}}
+		if err := skin.fetchWithTextureProperty(ctx, profileTextureProperty, "Skin"); err != nil {
+			return profile, errors.Wrap(err, "unable to FetchProfile")
+		}
+		profile.Skin = skin
+		profile.Stale = profile.Stale || skin.Stale
+	}
+
+	if profileTextureProperty.Textures.Cape.URL != "" {
+		cape := &Cape{Texture{Mc: mc}}
+		if err := cape.fetchWithTextureProperty(ctx, profileTextureProperty, "Cape"); err != nil {
+			return profile, errors.Wrap(err, "unable to FetchProfile")
+		}
+		profile.Cape = cape
+		profile.Stale = profile.Stale || cape.Stale
+	}
+
+	return profile, nil
+}
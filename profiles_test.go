@@ -4,6 +4,7 @@ package minecraft
 import (
 	"testing"
 
+	"github.com/pkg/errors"
 	. "github.com/smartystreets/goconvey/convey"
 )
 
@@ -156,4 +157,81 @@ func TestProfiles(t *testing.T) {
 
 	})
 
+	Convey("Test Profile error causes", t, func() {
+
+		Convey("Missing players should be ErrUserNotFound", func() {
+			_, err := mcTest.GetAPIProfile("skmkj88200aklk")
+
+			So(errors.Cause(err), ShouldEqual, ErrUserNotFound)
+		})
+
+		Convey("Rate limits should be ErrRateLimited", func() {
+			_, err := mcTest.GetSessionProfile("00000000000000000000000000000001")
+
+			So(errors.Cause(err), ShouldEqual, ErrRateLimited)
+		})
+
+	})
+
+	Convey("Test DashUUID", t, func() {
+
+		Convey("Plain UUIDs should be dashed", func() {
+			uuid, err := DashUUID("d9135e082f2244c89cb0bee234155292")
+
+			So(err, ShouldBeNil)
+			So(uuid, ShouldEqual, "d9135e08-2f22-44c8-9cb0-bee234155292")
+		})
+
+		Convey("Dashed UUIDs should be left alone", func() {
+			uuid, err := DashUUID("d9135e08-2f22-44c8-9cb0-bee234155292")
+
+			So(err, ShouldBeNil)
+			So(uuid, ShouldEqual, "d9135e08-2f22-44c8-9cb0-bee234155292")
+		})
+
+		Convey("Usernames should gracefully error", func() {
+			_, err := DashUUID("clone1018")
+
+			So(err.Error(), ShouldEqual, `unable to DashUUID: "clone1018" is not a UUID`)
+		})
+
+	})
+
+	Convey("Test FetchProfile", t, func() {
+
+		Convey("citricsquid should have a skin and cape", func() {
+			profile, err := mcTest.FetchProfile("citricsquid")
+
+			So(err, ShouldBeNil)
+			So(profile.UUID, ShouldEqual, "48a0a7e4d5594873a617dc189f76a8a1")
+			So(profile.Username, ShouldEqual, "citricsquid")
+			So(profile.Skin.Hash, ShouldEqual, "c05454f331fa93b3e38866a9ec52c467")
+			So(profile.Cape.Hash, ShouldEqual, "8cbf8786caba2f05383cf887be592ee6")
+		})
+
+		Convey("clone1018 should have no cape", func() {
+			profile, err := mcTest.FetchProfile("d9135e08-2f22-44c8-9cb0-bee234155292")
+
+			So(err, ShouldBeNil)
+			So(profile.Username, ShouldEqual, "clone1018")
+			So(profile.Skin.Hash, ShouldEqual, "a04a26d10218668a632e419ab073cf57")
+			So(profile.Cape, ShouldBeNil)
+		})
+
+		Convey("Missing players should gracefully error", func() {
+			_, err := mcTest.FetchProfile("10000000000000000000000000000000")
+
+			So(err.Error(), ShouldEqual, "unable to FetchProfile: unable to GetSessionProfile: user not found")
+			So(errors.Cause(err), ShouldEqual, ErrUserNotFound)
+		})
+
+		Convey("Broken textures should gracefully error", func() {
+			profile, err := mcTest.FetchProfile("00000000000000000000000000000010")
+
+			So(err.Error(), ShouldEqual, "unable to FetchProfile: FetchWithTextureProperty failed: unable to Fetch Texture: apiRequest HTTP 404 Not Found")
+			So(profile.Username, ShouldEqual, "404STexture")
+		})
+
+	})
+
 }
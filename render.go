@@ -0,0 +1,93 @@
+package minecraft
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/pkg/errors"
+)
+
+// toNRGBA returns the image as NRGBA, converting it when needed
+func toNRGBA(img image.Image) *image.NRGBA {
+	if nrgba, ok := img.(*image.NRGBA); ok {
+		return nrgba
+	}
+
+	bounds := img.Bounds()
+	nrgba := image.NewNRGBA(image.Rect(0, 0, bounds.Dx(), bounds.Dy()))
+	draw.Draw(nrgba, nrgba.Bounds(), img, bounds.Min, draw.Src)
+	return nrgba
+}
+
+// faceImage copies a face of the box out of the texture, flipping it when the
+// box is mirrored
+func faceImage(img image.Image, box SkinBox, f BoxFace) *image.NRGBA {
+	rect := box.Face(f).Add(img.Bounds().Min)
+	face := image.NewNRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(face, face.Bounds(), img, rect.Min, draw.Src)
+
+	if box.Mirror {
+		return flipHorizontal(face)
+	}
+	return face
+}
+
+// flipHorizontal returns a left-right mirrored copy of the image
+func flipHorizontal(img *image.NRGBA) *image.NRGBA {
+	bounds := img.Bounds()
+	flipped := image.NewNRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			flipped.SetNRGBA(bounds.Max.X-1-(x-bounds.Min.X), y, img.NRGBAAt(x, y))
+		}
+	}
+	return flipped
+}
+
+// scaleNearest enlarges the image by a whole factor without any smoothing, so
+// the texture pixels stay crisp
+func scaleNearest(img *image.NRGBA, scale int) *image.NRGBA {
+	if scale == 1 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	scaled := image.NewNRGBA(image.Rect(0, 0, bounds.Dx()*scale, bounds.Dy()*scale))
+	for y := 0; y < scaled.Rect.Dy(); y++ {
+		for x := 0; x < scaled.Rect.Dx(); x++ {
+			scaled.SetNRGBA(x, y, img.NRGBAAt(bounds.Min.X+x/scale, bounds.Min.Y+y/scale))
+		}
+	}
+	return scaled
+}
+
+// checkScale ensures a render was asked for at a usable size
+func checkScale(scale int) error {
+	if scale < 1 {
+		return errors.Errorf("scale must be at least 1 (got %d)", scale)
+	}
+	return nil
+}
+
+// ResizeNearest scales the image (eg. a render) to exactly width x height
+// without any smoothing, so the texture pixels stay crisp
+func ResizeNearest(img image.Image, width, height int) *image.NRGBA {
+	return resizeNearest(toNRGBA(img), width, height)
+}
+
+// resizeNearest scales the image to exactly width x height without any
+// smoothing
+func resizeNearest(img *image.NRGBA, width, height int) *image.NRGBA {
+	bounds := img.Bounds()
+	if bounds.Dx() == width && bounds.Dy() == height {
+		return img
+	}
+
+	resized := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			resized.SetNRGBA(x, y, img.NRGBAAt(bounds.Min.X+x*bounds.Dx()/width, bounds.Min.Y+y*bounds.Dy()/height))
+		}
+	}
+	return resized
+}
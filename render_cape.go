@@ -0,0 +1,142 @@
+package minecraft
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/pkg/errors"
+)
+
+// Front returns the outside of the cape, as seen from behind the player
+func (c *Cape) Front() (*image.NRGBA, error) {
+	box, err := c.CapeBox()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get cape Front")
+	}
+	return faceImage(c.Image, box, FaceFront), nil
+}
+
+// Back returns the inside of the cape, as seen from in front of the player
+func (c *Cape) Back() (*image.NRGBA, error) {
+	box, err := c.CapeBox()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get cape Back")
+	}
+	return faceImage(c.Image, box, FaceBack), nil
+}
+
+// Render returns the outside of the cape enlarged by scale (a scale of 1 gives
+// a 10x16 image for a standard cape)
+func (c *Cape) Render(scale int) (*image.NRGBA, error) {
+	if err := checkScale(scale); err != nil {
+		return nil, errors.Wrap(err, "unable to Render cape")
+	}
+
+	front, err := c.Front()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to Render cape")
+	}
+	return scaleNearest(front, scale), nil
+}
+
+// RenderElytra returns both wings of the elytra side by side, as seen from
+// behind the player, enlarged by scale (a scale of 1 gives a 20x20 image)
+func (c *Cape) RenderElytra(scale int) (*image.NRGBA, error) {
+	if err := checkScale(scale); err != nil {
+		return nil, errors.Wrap(err, "unable to RenderElytra")
+	}
+
+	box, err := c.ElytraBox()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to RenderElytra")
+	}
+
+	// From behind the left wing is on the left, the right wing mirrors it
+	left := faceImage(c.Image, box, FaceFront)
+	right := flipHorizontal(left)
+
+	elytra := image.NewNRGBA(image.Rect(0, 0, box.W*2, box.H))
+	draw.Draw(elytra, left.Bounds(), left, image.Point{}, draw.Src)
+	draw.Draw(elytra, right.Bounds().Add(image.Pt(box.W, 0)), right, image.Point{}, draw.Src)
+
+	return scaleNearest(elytra, scale), nil
+}
+
+// RenderBodyWithCape returns the front of the player with the inside of the
+// cape showing around them
+func (s *Skin) RenderBodyWithCape(cape *Cape, scale int, overlay bool) (*image.NRGBA, error) {
+	img, err := s.renderWithCape(cape, scale, overlay, false)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to RenderBodyWithCape")
+	}
+	return img, nil
+}
+
+// RenderBodyBackWithCape returns the back of the player with the cape hanging
+// over them
+func (s *Skin) RenderBodyBackWithCape(cape *Cape, scale int, overlay bool) (*image.NRGBA, error) {
+	img, err := s.renderWithCape(cape, scale, overlay, true)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to RenderBodyBackWithCape")
+	}
+	return img, nil
+}
+
+func (s *Skin) renderWithCape(cape *Cape, scale int, overlay bool, back bool) (*image.NRGBA, error) {
+	if err := checkScale(scale); err != nil {
+		return nil, err
+	}
+
+	skinUnit, err := s.layoutUnit()
+	if err != nil {
+		return nil, err
+	}
+	capeUnit, err := cape.layoutUnit()
+	if err != nil {
+		return nil, err
+	}
+
+	var body, capeFace *image.NRGBA
+	if back {
+		body, err = s.RenderBodyBack(1, overlay)
+		if err == nil {
+			capeFace, err = cape.Front()
+		}
+	} else {
+		body, err = s.RenderBody(1, overlay)
+		if err == nil {
+			capeFace, err = cape.Back()
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The skin and cape might not share a resolution, so bring them both up to
+	// a common one
+	unit := lcm(skinUnit, capeUnit)
+	body = scaleNearest(body, unit/skinUnit)
+	capeFace = scaleNearest(capeFace, unit/capeUnit)
+
+	// The cape hangs from the shoulders, a pixel wider than the body each side
+	canvas := image.NewNRGBA(body.Bounds())
+	capeRect := capeFace.Bounds().Add(image.Pt(3*unit, 8*unit))
+	if back {
+		draw.Draw(canvas, body.Bounds(), body, image.Point{}, draw.Src)
+		draw.Draw(canvas, capeRect, capeFace, image.Point{}, draw.Over)
+	} else {
+		draw.Draw(canvas, capeRect, capeFace, image.Point{}, draw.Src)
+		draw.Draw(canvas, body.Bounds(), body, image.Point{}, draw.Over)
+	}
+
+	return scaleNearest(canvas, scale), nil
+}
+
+// lcm returns the lowest common multiple of two positive numbers
+func lcm(a, b int) int {
+	x, y := a, b
+	for y != 0 {
+		x, y = y, x%y
+	}
+	return a / x * b
+}
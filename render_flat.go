@@ -0,0 +1,109 @@
+package minecraft
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/pkg/errors"
+)
+
+// flatFace places a face of a part on a flat render, in texture pixels
+type flatFace struct {
+	part SkinPart
+	face BoxFace
+	x, y int
+}
+
+// RenderHead returns the front of the head, optionally with the hat overlay,
+// enlarged by scale (a scale of 1 gives an 8x8 image for a standard skin)
+func (s *Skin) RenderHead(scale int, overlay bool) (*image.NRGBA, error) {
+	img, err := s.renderFlat([]flatFace{{PartHead, FaceFront, 0, 0}}, 8, 8, scale, overlay)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to RenderHead")
+	}
+	return img, nil
+}
+
+// RenderBody returns the front of the player, optionally with the overlays,
+// enlarged by scale (a scale of 1 gives a 16x32 image for a standard skin)
+func (s *Skin) RenderBody(scale int, overlay bool) (*image.NRGBA, error) {
+	armWidth := s.armWidth()
+
+	// The player's right side is on the left as we are facing them
+	img, err := s.renderFlat([]flatFace{
+		{PartHead, FaceFront, 4, 0},
+		{PartBody, FaceFront, 4, 8},
+		{PartRightArm, FaceFront, 4 - armWidth, 8},
+		{PartLeftArm, FaceFront, 12, 8},
+		{PartRightLeg, FaceFront, 4, 20},
+		{PartLeftLeg, FaceFront, 8, 20},
+	}, 16, 32, scale, overlay)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to RenderBody")
+	}
+	return img, nil
+}
+
+// RenderBodyBack returns the back of the player, optionally with the overlays,
+// enlarged by scale (a scale of 1 gives a 16x32 image for a standard skin)
+func (s *Skin) RenderBodyBack(scale int, overlay bool) (*image.NRGBA, error) {
+	armWidth := s.armWidth()
+
+	img, err := s.renderFlat([]flatFace{
+		{PartHead, FaceBack, 4, 0},
+		{PartBody, FaceBack, 4, 8},
+		{PartLeftArm, FaceBack, 4 - armWidth, 8},
+		{PartRightArm, FaceBack, 12, 8},
+		{PartLeftLeg, FaceBack, 4, 20},
+		{PartRightLeg, FaceBack, 8, 20},
+	}, 16, 32, scale, overlay)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to RenderBodyBack")
+	}
+	return img, nil
+}
+
+// armWidth is the width of the arms in texture pixels
+func (s *Skin) armWidth() int {
+	if s.IsSlim() {
+		return 3
+	}
+	return 4
+}
+
+// renderFlat draws the faces onto a width x height (in texture pixels) canvas,
+// with the overlays on top of the base layer
+func (s *Skin) renderFlat(faces []flatFace, width, height, scale int, overlay bool) (*image.NRGBA, error) {
+	if err := checkScale(scale); err != nil {
+		return nil, err
+	}
+	unit, err := s.layoutUnit()
+	if err != nil {
+		return nil, err
+	}
+
+	canvas := image.NewNRGBA(image.Rect(0, 0, width*unit, height*unit))
+	layers := []SkinLayer{LayerBase}
+	if overlay {
+		layers = append(layers, LayerOverlay)
+	}
+
+	for _, layer := range layers {
+		for _, f := range faces {
+			box, ok := s.Box(f.part, layer)
+			if !ok {
+				continue
+			}
+
+			src := faceImage(s.Image, box, f.face)
+			dst := src.Bounds().Add(image.Pt(f.x*unit, f.y*unit))
+			if layer == LayerBase {
+				draw.Draw(canvas, dst, src, image.Point{}, draw.Src)
+			} else {
+				draw.Draw(canvas, dst, src, image.Point{}, draw.Over)
+			}
+		}
+	}
+
+	return scaleNearest(canvas, scale), nil
+}
@@ -0,0 +1,83 @@
+package minecraft
+
+import (
+	"image"
+	"image/color"
+	"image/gif"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// spinFrames is how many frames a turn takes when not given
+	spinFrames = 24
+	// spinDelay is how long each frame shows when not given, in 100ths of a second
+	spinDelay = 8
+)
+
+// SpinOptions controls the rotating GIF renderers
+type SpinOptions struct {
+	// IsoOptions for each frame, where Yaw is the angle to start from
+	IsoOptions
+	// Frames in a full turn (24 if 0)
+	Frames int
+	// Delay between frames in 100ths of a second (8 if 0)
+	Delay int
+	// Background to flatten the frames onto, otherwise they are transparent
+	Background color.Color
+}
+
+// RenderHeadSpin returns a looping GIF of the isometric head making a full
+// turn, opts.Size pixels square
+func (s *Skin) RenderHeadSpin(opts SpinOptions) (*gif.GIF, error) {
+	anim, err := renderSpin(opts, s.RenderHeadIsoWithOptions)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to RenderHeadSpin")
+	}
+	return anim, nil
+}
+
+// RenderBodySpin returns a looping GIF of the isometric body making a full
+// turn, opts.Size pixels high and half as wide
+func (s *Skin) RenderBodySpin(opts SpinOptions) (*gif.GIF, error) {
+	anim, err := renderSpin(opts, s.RenderBodyIso)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to RenderBodySpin")
+	}
+	return anim, nil
+}
+
+// renderSpin renders each frame of the turn
+func renderSpin(opts SpinOptions, render func(IsoOptions) (*image.NRGBA, error)) (*gif.GIF, error) {
+	if opts.Frames == 0 {
+		opts.Frames = spinFrames
+	}
+	if opts.Delay == 0 {
+		opts.Delay = spinDelay
+	}
+	if opts.Frames < 1 || opts.Frames > 360 {
+		return nil, errors.Errorf("frames must be between 1 and 360 (got %d)", opts.Frames)
+	}
+	if opts.Delay < 0 {
+		return nil, errors.Errorf("delay must not be negative (got %d)", opts.Delay)
+	}
+
+	anim := &gif.GIF{}
+	start := opts.Yaw
+	for i := 0; i < opts.Frames; i++ {
+		frameOpts := opts.IsoOptions
+		frameOpts.Yaw = start + 360*float64(i)/float64(opts.Frames)
+
+		frame, err := render(frameOpts)
+		if err != nil {
+			return nil, err
+		}
+
+		anim.Image = append(anim.Image, palettize(frame, opts.Background))
+		anim.Delay = append(anim.Delay, opts.Delay)
+		// Clear each frame before the next, so transparent frames don't
+		// show through to each other
+		anim.Disposal = append(anim.Disposal, gif.DisposalBackground)
+	}
+	return anim, nil
+}
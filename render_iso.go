@@ -0,0 +1,279 @@
+package minecraft
+
+import (
+	"image"
+	"image/color"
+	"math"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// isoYaw turns the player so we see their front and their right side
+	isoYaw = 45.0
+	// isoPitch tilts the camera down so we see the top of the player
+	isoPitch = 30.0
+
+	// hatInflation is how far (in texture pixels) the hat shell sits outside the head
+	hatInflation = 0.5
+)
+
+// faceShade darkens the faces the way Minecraft lights them, so the cuboids
+// read as solid even with a flat texture
+var faceShade = map[BoxFace]float64{
+	FaceTop:    1.0,
+	FaceBottom: 0.5,
+	FaceFront:  0.8,
+	FaceBack:   0.8,
+	FaceRight:  0.6,
+	FaceLeft:   0.6,
+}
+
+// vec3 is a point or direction in model space: x runs to the player's left, y
+// up and z out of the player's front (all in image pixels of the texture)
+type vec3 struct {
+	x, y, z float64
+}
+
+func (a vec3) add(b vec3) vec3 {
+	return vec3{a.x + b.x, a.y + b.y, a.z + b.z}
+}
+
+// isoQuad is a textured parallelogram: the texture's columns run along u and
+// its rows along v, starting from origin
+type isoQuad struct {
+	origin, u, v vec3
+	normal       vec3
+	texture      *image.NRGBA
+	shade        float64
+}
+
+// boxQuads builds the six faces of a box whose minimum corner is at min,
+// grown by inflate on every side (used for the overlay shells)
+func boxQuads(img image.Image, box SkinBox, min vec3, inflate float64) []isoQuad {
+	x0, y0, z0 := min.x-inflate, min.y-inflate, min.z-inflate
+	w := float64(box.W) + inflate*2
+	h := float64(box.H) + inflate*2
+	d := float64(box.D) + inflate*2
+	x1, y1, z1 := x0+w, y0+h, z0+d
+
+	quad := func(face BoxFace, origin, u, v, normal vec3) isoQuad {
+		return isoQuad{
+			origin:  origin,
+			u:       u,
+			v:       v,
+			normal:  normal,
+			texture: faceImage(img, box, face),
+			shade:   faceShade[face],
+		}
+	}
+
+	return []isoQuad{
+		quad(FaceTop, vec3{x0, y1, z0}, vec3{w, 0, 0}, vec3{0, 0, d}, vec3{0, 1, 0}),
+		quad(FaceBottom, vec3{x0, y0, z1}, vec3{w, 0, 0}, vec3{0, 0, -d}, vec3{0, -1, 0}),
+		quad(FaceRight, vec3{x0, y1, z0}, vec3{0, 0, d}, vec3{0, -h, 0}, vec3{-1, 0, 0}),
+		quad(FaceFront, vec3{x0, y1, z1}, vec3{w, 0, 0}, vec3{0, -h, 0}, vec3{0, 0, 1}),
+		quad(FaceLeft, vec3{x1, y1, z1}, vec3{0, 0, -d}, vec3{0, -h, 0}, vec3{1, 0, 0}),
+		quad(FaceBack, vec3{x1, y1, z0}, vec3{-w, 0, 0}, vec3{0, -h, 0}, vec3{0, 0, -1}),
+	}
+}
+
+// isoCamera is an orthographic camera turned by yaw around the player and
+// tilted down by pitch (both in degrees)
+type isoCamera struct {
+	sinYaw, cosYaw     float64
+	sinPitch, cosPitch float64
+}
+
+func newIsoCamera(yaw, pitch float64) isoCamera {
+	yaw, pitch = yaw*math.Pi/180, pitch*math.Pi/180
+	return isoCamera{
+		sinYaw: math.Sin(yaw), cosYaw: math.Cos(yaw),
+		sinPitch: math.Sin(pitch), cosPitch: math.Cos(pitch),
+	}
+}
+
+// view moves a point into camera space: x right, y down the screen and z
+// towards the viewer
+func (c isoCamera) view(p vec3) vec3 {
+	x := p.x*c.cosYaw + p.z*c.sinYaw
+	z := -p.x*c.sinYaw + p.z*c.cosYaw
+	y := p.y*c.cosPitch - z*c.sinPitch
+	z = p.y*c.sinPitch + z*c.cosPitch
+	return vec3{x, -y, z}
+}
+
+// rasterize draws the quads into a width x height image, scaled to fit and
+// centred (the model should be centred on the y axis). Faces pointing away
+// from the camera are skipped and a depth buffer sorts out the rest, so the
+// quads can be given in any order (although translucent quads should come last).
+func rasterize(quads []isoQuad, camera isoCamera, width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+
+	// Fit the model to the image using a cylinder around it, so the scale
+	// does not change as the model is turned
+	radius, minY, maxY := 0.0, math.Inf(1), math.Inf(-1)
+	for _, q := range quads {
+		for _, corner := range []vec3{q.origin, q.origin.add(q.u), q.origin.add(q.v), q.origin.add(q.u).add(q.v)} {
+			r := math.Hypot(corner.x, corner.z)
+			radius = math.Max(radius, r)
+			minY = math.Min(minY, -corner.y*camera.cosPitch-r*camera.sinPitch)
+			maxY = math.Max(maxY, -corner.y*camera.cosPitch+r*camera.sinPitch)
+		}
+	}
+	minX, maxX := -radius, radius
+	scale := math.Min(float64(width)/(maxX-minX), float64(height)/(maxY-minY))
+	offsetX := (float64(width) - (maxX-minX)*scale) / 2
+	offsetY := (float64(height) - (maxY-minY)*scale) / 2
+
+	toScreen := func(p vec3) vec3 {
+		p = camera.view(p)
+		return vec3{(p.x-minX)*scale + offsetX, (p.y-minY)*scale + offsetY, p.z}
+	}
+
+	depth := make([]float64, width*height)
+	for i := range depth {
+		depth[i] = math.Inf(-1)
+	}
+
+	for _, q := range quads {
+		// Back-face culling
+		if camera.view(q.normal).z <= 0 {
+			continue
+		}
+
+		origin := toScreen(q.origin)
+		u := toScreen(q.origin.add(q.u))
+		v := toScreen(q.origin.add(q.v))
+		u = vec3{u.x - origin.x, u.y - origin.y, u.z - origin.z}
+		v = vec3{v.x - origin.x, v.y - origin.y, v.z - origin.z}
+
+		det := u.x*v.y - u.y*v.x
+		if math.Abs(det) < 1e-9 {
+			// Edge on to the camera
+			continue
+		}
+
+		far := origin.add(u).add(v)
+		x0 := int(math.Floor(math.Min(math.Min(origin.x, origin.x+u.x), math.Min(origin.x+v.x, far.x))))
+		x1 := int(math.Ceil(math.Max(math.Max(origin.x, origin.x+u.x), math.Max(origin.x+v.x, far.x))))
+		y0 := int(math.Floor(math.Min(math.Min(origin.y, origin.y+u.y), math.Min(origin.y+v.y, far.y))))
+		y1 := int(math.Ceil(math.Max(math.Max(origin.y, origin.y+u.y), math.Max(origin.y+v.y, far.y))))
+
+		texW, texH := q.texture.Rect.Dx(), q.texture.Rect.Dy()
+		for py := maxInt(y0, 0); py < minInt(y1, height); py++ {
+			for px := maxInt(x0, 0); px < minInt(x1, width); px++ {
+				// Solve origin + s*u + t*v = pixel centre
+				qx, qy := float64(px)+0.5-origin.x, float64(py)+0.5-origin.y
+				s := (qx*v.y - qy*v.x) / det
+				t := (u.x*qy - u.y*qx) / det
+				if s < 0 || s >= 1 || t < 0 || t >= 1 {
+					continue
+				}
+
+				z := origin.z + s*u.z + t*v.z
+				i := py*width + px
+				if z < depth[i] {
+					continue
+				}
+
+				texel := q.texture.NRGBAAt(q.texture.Rect.Min.X+int(s*float64(texW)), q.texture.Rect.Min.Y+int(t*float64(texH)))
+				if texel.A == 0 {
+					continue
+				}
+
+				shaded := color.NRGBA{
+					R: uint8(float64(texel.R) * q.shade),
+					G: uint8(float64(texel.G) * q.shade),
+					B: uint8(float64(texel.B) * q.shade),
+					A: texel.A,
+				}
+				if texel.A == 0xff {
+					img.SetNRGBA(px, py, shaded)
+					depth[i] = z
+				} else {
+					img.SetNRGBA(px, py, blendOver(img.NRGBAAt(px, py), shaded))
+				}
+			}
+		}
+	}
+
+	return img
+}
+
+// blendOver composites src over dst
+func blendOver(dst, src color.NRGBA) color.NRGBA {
+	sa, da := float64(src.A)/0xff, float64(dst.A)/0xff
+	a := sa + da*(1-sa)
+	if a == 0 {
+		return color.NRGBA{}
+	}
+
+	mix := func(s, d uint8) uint8 {
+		return uint8((float64(s)*sa + float64(d)*da*(1-sa)) / a)
+	}
+	return color.NRGBA{mix(src.R, dst.R), mix(src.G, dst.G), mix(src.B, dst.B), uint8(a*0xff + 0.5)}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// headQuads builds the head centred on the origin, with its base at y=0
+func (s *Skin) headQuads(overlay bool) ([]isoQuad, error) {
+	unit, err := s.layoutUnit()
+	if err != nil {
+		return nil, err
+	}
+
+	head, _ := s.Box(PartHead, LayerBase)
+	min := vec3{-float64(head.W) / 2, 0, -float64(head.D) / 2}
+
+	quads := boxQuads(s.Image, head, min, 0)
+	if hat, ok := s.Box(PartHead, LayerOverlay); ok && overlay {
+		quads = append(quads, boxQuads(s.Image, hat, min, hatInflation*float64(unit))...)
+	}
+	return quads, nil
+}
+
+// IsoOptions controls the isometric renderers
+type IsoOptions struct {
+	// Size is the height of the image in pixels (and the width for heads)
+	Size int
+	// Overlay draws the overlay shells over the base layer
+	Overlay bool
+	// Yaw turns the player clockwise (as seen from above) away from the
+	// standard three-quarter view, in degrees
+	Yaw float64
+}
+
+// RenderHeadIso returns an isometric render of the head as a cube, optionally
+// with the hat, fitted to a size x size image
+func (s *Skin) RenderHeadIso(size int, overlay bool) (*image.NRGBA, error) {
+	return s.RenderHeadIsoWithOptions(IsoOptions{Size: size, Overlay: overlay})
+}
+
+// RenderHeadIsoWithOptions returns an isometric render of the head as a cube,
+// fitted to a square image
+func (s *Skin) RenderHeadIsoWithOptions(opts IsoOptions) (*image.NRGBA, error) {
+	if opts.Size < 1 {
+		return nil, errors.Errorf("unable to RenderHeadIso: size must be at least 1 (got %d)", opts.Size)
+	}
+
+	quads, err := s.headQuads(opts.Overlay)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to RenderHeadIso")
+	}
+
+	return rasterize(quads, newIsoCamera(isoYaw+opts.Yaw, isoPitch), opts.Size, opts.Size), nil
+}
@@ -0,0 +1,73 @@
+package minecraft
+
+import (
+	"image"
+
+	"github.com/pkg/errors"
+)
+
+// clothingInflation is how far (in texture pixels) the jacket, sleeves and
+// pants shells sit outside the body and limbs
+const clothingInflation = 0.25
+
+// bodyQuads builds the whole player standing on y=0, centred on the origin
+func (s *Skin) bodyQuads(overlay bool) ([]isoQuad, error) {
+	unit, err := s.layoutUnit()
+	if err != nil {
+		return nil, err
+	}
+	u := float64(unit)
+	arm := float64(s.armWidth())
+
+	// Where the minimum corner of each part sits, in texture pixels. The
+	// player's right is towards -x.
+	positions := map[SkinPart]vec3{
+		PartRightLeg: {-4, 0, -2},
+		PartLeftLeg:  {0, 0, -2},
+		PartBody:     {-4, 12, -2},
+		PartRightArm: {-4 - arm, 12, -2},
+		PartLeftArm:  {4, 12, -2},
+		PartHead:     {-4, 24, -4},
+	}
+
+	var base, overlays []isoQuad
+	for _, part := range SkinParts {
+		min := positions[part]
+		min = vec3{min.x * u, min.y * u, min.z * u}
+
+		box, ok := s.Box(part, LayerBase)
+		if !ok {
+			continue
+		}
+		base = append(base, boxQuads(s.Image, box, min, 0)...)
+
+		if !overlay {
+			continue
+		}
+		if box, ok = s.Box(part, LayerOverlay); ok {
+			inflate := clothingInflation
+			if part == PartHead {
+				inflate = hatInflation
+			}
+			overlays = append(overlays, boxQuads(s.Image, box, min, inflate*u)...)
+		}
+	}
+
+	// Overlays last so their translucent pixels blend over the base layer
+	return append(base, overlays...), nil
+}
+
+// RenderBodyIso returns an isometric render of the whole player, fitted to an
+// image opts.Size high and half as wide
+func (s *Skin) RenderBodyIso(opts IsoOptions) (*image.NRGBA, error) {
+	if opts.Size < 2 {
+		return nil, errors.Errorf("unable to RenderBodyIso: size must be at least 2 (got %d)", opts.Size)
+	}
+
+	quads, err := s.bodyQuads(opts.Overlay)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to RenderBodyIso")
+	}
+
+	return rasterize(quads, newIsoCamera(isoYaw+opts.Yaw, isoPitch), opts.Size/2, opts.Size), nil
+}
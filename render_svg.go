@@ -0,0 +1,128 @@
+package minecraft
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strconv"
+
+	"github.com/pkg/errors"
+)
+
+// RenderHeadSVG writes the front of the head, optionally with the hat overlay,
+// as an SVG sized at scale pixels per texture pixel
+func (s *Skin) RenderHeadSVG(w io.Writer, scale int, overlay bool) error {
+	img, err := s.RenderHead(1, overlay)
+	if err == nil {
+		err = writeSVG(w, img, scale)
+	}
+	if err != nil {
+		return errors.Wrap(err, "unable to RenderHeadSVG")
+	}
+	return nil
+}
+
+// RenderBodySVG writes the front of the player, optionally with the overlays,
+// as an SVG sized at scale pixels per texture pixel
+func (s *Skin) RenderBodySVG(w io.Writer, scale int, overlay bool) error {
+	img, err := s.RenderBody(1, overlay)
+	if err == nil {
+		err = writeSVG(w, img, scale)
+	}
+	if err != nil {
+		return errors.Wrap(err, "unable to RenderBodySVG")
+	}
+	return nil
+}
+
+// RenderBodyBackSVG writes the back of the player, optionally with the
+// overlays, as an SVG sized at scale pixels per texture pixel
+func (s *Skin) RenderBodyBackSVG(w io.Writer, scale int, overlay bool) error {
+	img, err := s.RenderBodyBack(1, overlay)
+	if err == nil {
+		err = writeSVG(w, img, scale)
+	}
+	if err != nil {
+		return errors.Wrap(err, "unable to RenderBodyBackSVG")
+	}
+	return nil
+}
+
+// svgRect is a run of same coloured pixels
+type svgRect struct {
+	x, y, w, h int
+	c          color.NRGBA
+}
+
+// mergeRects covers the visible pixels of the image with as few same coloured
+// rectangles as it can find, by growing each one right and then down
+func mergeRects(img *image.NRGBA) []svgRect {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	done := make([]bool, width*height)
+
+	at := func(x, y int) color.NRGBA {
+		return img.NRGBAAt(bounds.Min.X+x, bounds.Min.Y+y)
+	}
+
+	var rects []svgRect
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := at(x, y)
+			if done[y*width+x] || c.A == 0 {
+				continue
+			}
+
+			w := 1
+			for x+w < width && !done[y*width+x+w] && at(x+w, y) == c {
+				w++
+			}
+
+			h := 1
+		grow:
+			for y+h < height {
+				for i := x; i < x+w; i++ {
+					if done[(y+h)*width+i] || at(i, y+h) != c {
+						break grow
+					}
+				}
+				h++
+			}
+
+			for j := y; j < y+h; j++ {
+				for i := x; i < x+w; i++ {
+					done[j*width+i] = true
+				}
+			}
+			rects = append(rects, svgRect{x, y, w, h, c})
+		}
+	}
+	return rects
+}
+
+// writeSVG writes the image as an SVG made of rectangles, one unit per pixel
+func writeSVG(w io.Writer, img *image.NRGBA, scale int) error {
+	if err := checkScale(scale); err != nil {
+		return err
+	}
+
+	bounds := img.Bounds()
+	buf := &bytes.Buffer{}
+	fmt.Fprintf(buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		bounds.Dx()*scale, bounds.Dy()*scale, bounds.Dx(), bounds.Dy())
+	buf.WriteString("\n")
+
+	for _, r := range mergeRects(img) {
+		fmt.Fprintf(buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="#%02x%02x%02x"`, r.x, r.y, r.w, r.h, r.c.R, r.c.G, r.c.B)
+		if r.c.A != 0xff {
+			fmt.Fprintf(buf, ` fill-opacity="%s"`, strconv.FormatFloat(float64(r.c.A)/0xff, 'f', 3, 64))
+		}
+		buf.WriteString("/>\n")
+	}
+	buf.WriteString("</svg>\n")
+
+	_, err := buf.WriteTo(w)
+	return err
+}
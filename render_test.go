@@ -0,0 +1,474 @@
+// render_test.go
+package minecraft
+
+import (
+	"bytes"
+	"encoding/xml"
+	"image"
+	"image/color"
+	"image/gif"
+	"testing"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// testSkin returns a skin where every box is filled with its own colour
+func testSkin(width, height int, model string) Skin {
+	skin := Skin{Texture{Image: image.NewNRGBA(image.Rect(0, 0, width, height)), Model: model}}
+	for i, box := range skin.Boxes() {
+		for _, face := range BoxFaces {
+			fillRect(skin.Image.(*image.NRGBA), box.Face(face), boxColour(i, face))
+		}
+	}
+	return skin
+}
+
+func boxColour(box int, face BoxFace) color.NRGBA {
+	return color.NRGBA{uint8(box * 20), uint8(face * 40), 100, 255}
+}
+
+func fillRect(img *image.NRGBA, rect image.Rectangle, c color.NRGBA) {
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+}
+
+func TestSkinLayout(t *testing.T) {
+
+	Convey("Test Skin.Box", t, func() {
+
+		Convey("Modern skins have every box", func() {
+			skin := testSkin(64, 64, SkinModelClassic)
+
+			So(skin.IsLegacy(), ShouldBeFalse)
+			So(skin.Boxes(), ShouldHaveLength, 12)
+
+			box, ok := skin.Box(PartHead, LayerBase)
+			So(ok, ShouldBeTrue)
+			So(box.Face(FaceFront), ShouldResemble, image.Rect(8, 8, 16, 16))
+			So(box.Face(FaceBack), ShouldResemble, image.Rect(24, 8, 32, 16))
+		})
+
+		Convey("Legacy skins only have a hat and mirror the left limbs", func() {
+			skin := testSkin(64, 32, SkinModelClassic)
+
+			So(skin.IsLegacy(), ShouldBeTrue)
+			So(skin.Boxes(), ShouldHaveLength, 7)
+
+			_, ok := skin.Box(PartBody, LayerOverlay)
+			So(ok, ShouldBeFalse)
+
+			leftArm, ok := skin.Box(PartLeftArm, LayerBase)
+			So(ok, ShouldBeTrue)
+			So(leftArm.Mirror, ShouldBeTrue)
+			So(leftArm.Name, ShouldEqual, "left_arm")
+			So(leftArm.Face(FaceFront), ShouldResemble, image.Rect(44, 20, 48, 32))
+			So(leftArm.Face(FaceLeft), ShouldResemble, image.Rect(40, 20, 44, 32))
+		})
+
+		Convey("Slim skins have narrower arms", func() {
+			skin := testSkin(64, 64, SkinModelSlim)
+
+			arm, _ := skin.Box(PartRightArm, LayerOverlay)
+			So(arm.W, ShouldEqual, 3)
+			So(arm.Face(FaceBack), ShouldResemble, image.Rect(51, 36, 54, 48))
+		})
+
+		Convey("HD skins are scaled up", func() {
+			skin := testSkin(128, 128, SkinModelClassic)
+
+			box, _ := skin.Box(PartBody, LayerBase)
+			So(box.Face(FaceFront), ShouldResemble, image.Rect(40, 40, 56, 64))
+		})
+
+		Convey("Odd sized images have no boxes", func() {
+			skin := Skin{Texture{Image: image.NewNRGBA(image.Rect(0, 0, 50, 50))}}
+
+			So(skin.Boxes(), ShouldBeEmpty)
+		})
+
+	})
+
+}
+
+func TestRenderFlat(t *testing.T) {
+
+	Convey("Test Skin.RenderHead", t, func() {
+
+		Convey("Steve should render an 8x8 head", func() {
+			steve, _ := FetchSkinForSteve()
+			head, err := steve.RenderHead(1, true)
+
+			So(err, ShouldBeNil)
+			So(head.Bounds(), ShouldResemble, image.Rect(0, 0, 8, 8))
+			So(head.NRGBAAt(3, 4), ShouldResemble, steve.Image.(*image.NRGBA).NRGBAAt(11, 12))
+		})
+
+		Convey("Scale should enlarge the head", func() {
+			skin := testSkin(64, 64, SkinModelClassic)
+			head, err := skin.RenderHead(4, false)
+
+			So(err, ShouldBeNil)
+			So(head.Bounds(), ShouldResemble, image.Rect(0, 0, 32, 32))
+			So(head.NRGBAAt(31, 31), ShouldResemble, boxColour(0, FaceFront))
+		})
+
+		Convey("Overlays should be drawn over the base layer", func() {
+			skin := testSkin(64, 64, SkinModelClassic)
+			head, _ := skin.RenderHead(1, true)
+
+			So(head.NRGBAAt(0, 0), ShouldResemble, boxColour(6, FaceFront))
+		})
+
+		Convey("Bad renders should gracefully fail", func() {
+			skin := testSkin(64, 64, SkinModelClassic)
+			_, err := skin.RenderHead(0, false)
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to RenderHead: scale must be at least 1 (got 0)")
+
+			_, err = (&Skin{}).RenderHead(1, false)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to RenderHead: skin has no image")
+		})
+
+	})
+
+	Convey("Test Skin.RenderBody", t, func() {
+
+		Convey("Classic skins fill the whole width", func() {
+			skin := testSkin(64, 64, SkinModelClassic)
+			body, err := skin.RenderBody(2, false)
+
+			So(err, ShouldBeNil)
+			So(body.Bounds(), ShouldResemble, image.Rect(0, 0, 32, 64))
+			So(body.NRGBAAt(0, 16), ShouldResemble, boxColour(2, FaceFront))
+			So(body.NRGBAAt(31, 16), ShouldResemble, boxColour(3, FaceFront))
+			So(body.NRGBAAt(8, 63), ShouldResemble, boxColour(4, FaceFront))
+		})
+
+		Convey("Slim skins leave a gap beside the arms", func() {
+			skin := testSkin(64, 64, SkinModelSlim)
+			body, _ := skin.RenderBody(1, true)
+
+			So(body.NRGBAAt(0, 8).A, ShouldEqual, 0)
+			So(body.NRGBAAt(1, 8), ShouldResemble, boxColour(8, FaceFront))
+			So(body.NRGBAAt(14, 8), ShouldResemble, boxColour(9, FaceFront))
+			So(body.NRGBAAt(15, 8).A, ShouldEqual, 0)
+		})
+
+		Convey("The back shows the left arm on the left", func() {
+			skin := testSkin(64, 64, SkinModelClassic)
+			body, err := skin.RenderBodyBack(1, false)
+
+			So(err, ShouldBeNil)
+			So(body.NRGBAAt(0, 8), ShouldResemble, boxColour(3, FaceBack))
+			So(body.NRGBAAt(4, 0), ShouldResemble, boxColour(0, FaceBack))
+		})
+
+		Convey("Legacy skins mirror the right limbs", func() {
+			steve, _ := FetchSkinForSteve()
+			body, err := steve.RenderBody(1, false)
+
+			So(err, ShouldBeNil)
+			img := steve.Image.(*image.NRGBA)
+			So(body.NRGBAAt(12, 8), ShouldResemble, img.NRGBAAt(47, 20))
+			So(body.NRGBAAt(8, 20), ShouldResemble, img.NRGBAAt(7, 20))
+		})
+
+	})
+
+}
+
+func TestRenderIso(t *testing.T) {
+
+	Convey("Test Skin.RenderHeadIso", t, func() {
+
+		Convey("Steve should render a cube", func() {
+			steve, _ := FetchSkinForSteve()
+			head, err := steve.RenderHeadIso(64, true)
+
+			So(err, ShouldBeNil)
+			So(head.Bounds(), ShouldResemble, image.Rect(0, 0, 64, 64))
+			So(head.NRGBAAt(32, 32).A, ShouldEqual, 255)
+			So(head.NRGBAAt(0, 0).A, ShouldEqual, 0)
+			So(head.NRGBAAt(63, 63).A, ShouldEqual, 0)
+		})
+
+		Convey("Faces should be shaded", func() {
+			skin := testSkin(64, 64, SkinModelClassic)
+			head, _ := skin.RenderHeadIso(100, false)
+
+			// The front is on the right, the player's right side on the left
+			front := boxColour(0, FaceFront)
+			So(head.NRGBAAt(75, 60), ShouldResemble, color.NRGBA{uint8(float64(front.R) * 0.8), uint8(float64(front.G) * 0.8), 80, 255})
+			right := boxColour(0, FaceRight)
+			So(head.NRGBAAt(25, 60), ShouldResemble, color.NRGBA{uint8(float64(right.R) * 0.6), uint8(float64(right.G) * 0.6), 60, 255})
+			So(head.NRGBAAt(50, 10), ShouldResemble, boxColour(0, FaceTop))
+		})
+
+		Convey("Bad sizes should gracefully fail", func() {
+			steve, _ := FetchSkinForSteve()
+			_, err := steve.RenderHeadIso(0, true)
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to RenderHeadIso: size must be at least 1 (got 0)")
+		})
+
+	})
+
+	Convey("Test Skin.RenderBodyIso", t, func() {
+
+		Convey("Steve should render a whole player", func() {
+			steve, _ := FetchSkinForSteve()
+			body, err := steve.RenderBodyIso(IsoOptions{Size: 128, Overlay: true})
+
+			So(err, ShouldBeNil)
+			So(body.Bounds(), ShouldResemble, image.Rect(0, 0, 64, 128))
+			So(body.NRGBAAt(32, 64).A, ShouldEqual, 255)
+			So(body.NRGBAAt(0, 0).A, ShouldEqual, 0)
+		})
+
+		Convey("Yaw should turn the player", func() {
+			skin := testSkin(64, 64, SkinModelClassic)
+			front, _ := skin.RenderBodyIso(IsoOptions{Size: 128, Yaw: -45})
+			back, _ := skin.RenderBodyIso(IsoOptions{Size: 128, Yaw: 135})
+
+			So(front.NRGBAAt(32, 60), ShouldResemble, color.NRGBA{uint8(float64(boxColour(1, FaceFront).R) * 0.8), uint8(float64(boxColour(1, FaceFront).G) * 0.8), 80, 255})
+			So(back.NRGBAAt(32, 60), ShouldResemble, color.NRGBA{uint8(float64(boxColour(1, FaceBack).R) * 0.8), uint8(float64(boxColour(1, FaceBack).G) * 0.8), 80, 255})
+		})
+
+		Convey("Slim arms should be narrower", func() {
+			classic := testSkin(64, 64, SkinModelClassic)
+			slim := testSkin(64, 64, SkinModelSlim)
+			classicBody, _ := classic.RenderBodyIso(IsoOptions{Size: 128, Yaw: -45})
+			slimBody, _ := slim.RenderBodyIso(IsoOptions{Size: 128, Yaw: -45})
+
+			So(opaqueWidth(slimBody, 60), ShouldBeLessThan, opaqueWidth(classicBody, 60))
+		})
+
+		Convey("Bad sizes should gracefully fail", func() {
+			steve, _ := FetchSkinForSteve()
+			_, err := steve.RenderBodyIso(IsoOptions{Size: 1})
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to RenderBodyIso: size must be at least 2 (got 1)")
+		})
+
+	})
+
+}
+
+// opaqueWidth counts the opaque pixels along a row
+func opaqueWidth(img *image.NRGBA, y int) int {
+	width := 0
+	for x := 0; x < img.Rect.Dx(); x++ {
+		if img.NRGBAAt(x, y).A == 255 {
+			width++
+		}
+	}
+	return width
+}
+
+func TestRenderCape(t *testing.T) {
+
+	Convey("Test Cape layouts", t, func() {
+
+		Convey("Modern capes have an elytra", func() {
+			cape := Cape{Texture{Image: image.NewNRGBA(image.Rect(0, 0, 64, 32))}}
+
+			box, err := cape.ElytraBox()
+			So(err, ShouldBeNil)
+			So(box.Face(FaceFront), ShouldResemble, image.Rect(24, 2, 34, 22))
+		})
+
+		Convey("Legacy capes have no elytra", func() {
+			cape := Cape{Texture{Image: image.NewNRGBA(image.Rect(0, 0, 22, 17))}}
+
+			So(cape.IsLegacy(), ShouldBeTrue)
+			box, err := cape.CapeBox()
+			So(err, ShouldBeNil)
+			So(box.Face(FaceFront), ShouldResemble, image.Rect(1, 1, 11, 17))
+
+			_, err = cape.RenderElytra(1)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to RenderElytra: legacy capes have no elytra texture")
+		})
+
+		Convey("Odd sized capes should gracefully fail", func() {
+			cape := Cape{Texture{Image: image.NewNRGBA(image.Rect(0, 0, 30, 30))}}
+
+			_, err := cape.Render(1)
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to Render cape: unable to get cape Front: unsupported cape dimensions 30x30")
+		})
+
+	})
+
+	Convey("Test Cape renders", t, func() {
+		cape, _ := mcTest.FetchCapeUsername("citricsquid")
+		capeImg := cape.Image.(*image.NRGBA)
+
+		Convey("citricsquid cape should render", func() {
+			img, err := cape.Render(2)
+
+			So(err, ShouldBeNil)
+			So(img.Bounds(), ShouldResemble, image.Rect(0, 0, 20, 32))
+			So(img.NRGBAAt(0, 0), ShouldResemble, capeImg.NRGBAAt(1, 1))
+		})
+
+		Convey("citricsquid elytra should render both wings", func() {
+			img, err := cape.RenderElytra(1)
+
+			So(err, ShouldBeNil)
+			So(img.Bounds(), ShouldResemble, image.Rect(0, 0, 20, 20))
+			So(img.NRGBAAt(0, 0), ShouldResemble, img.NRGBAAt(19, 0))
+		})
+
+		Convey("The cape should hang over the back of the body", func() {
+			steve, _ := FetchSkinForSteve()
+			img, err := steve.RenderBodyBackWithCape(&cape, 1, true)
+
+			So(err, ShouldBeNil)
+			So(img.Bounds(), ShouldResemble, image.Rect(0, 0, 16, 32))
+			So(img.NRGBAAt(3, 8), ShouldResemble, capeImg.NRGBAAt(1, 1))
+			So(img.NRGBAAt(4, 0), ShouldResemble, steve.Image.(*image.NRGBA).NRGBAAt(24, 8))
+		})
+
+		Convey("The cape should be behind the front of the body", func() {
+			steve, _ := FetchSkinForSteve()
+			img, err := steve.RenderBodyWithCape(&cape, 1, true)
+			body, _ := steve.RenderBody(1, true)
+
+			So(err, ShouldBeNil)
+			So(img.NRGBAAt(8, 12), ShouldResemble, body.NRGBAAt(8, 12))
+		})
+
+	})
+
+}
+
+func TestRenderSVG(t *testing.T) {
+
+	Convey("Test Skin.RenderHeadSVG", t, func() {
+
+		Convey("A single colour head should be a single rectangle", func() {
+			skin := testSkin(64, 64, SkinModelClassic)
+			buf := &bytes.Buffer{}
+			err := skin.RenderHeadSVG(buf, 8, false)
+
+			So(err, ShouldBeNil)
+			So(buf.String(), ShouldEqual, `<svg xmlns="http://www.w3.org/2000/svg" width="64" height="64" viewBox="0 0 8 8" shape-rendering="crispEdges">
+<rect x="0" y="0" width="8" height="8" fill="#007864"/>
+</svg>
+`)
+		})
+
+		Convey("Translucent overlays should keep their opacity", func() {
+			skin := testSkin(64, 64, SkinModelClassic)
+			hat, _ := skin.Box(PartHead, LayerOverlay)
+			clearRect(skin.Image.(*image.NRGBA), hat.Face(FaceFront))
+			skin.Image.(*image.NRGBA).SetNRGBA(8, 8, color.NRGBA{})
+			skin.Image.(*image.NRGBA).SetNRGBA(40, 8, color.NRGBA{255, 255, 255, 128})
+
+			buf := &bytes.Buffer{}
+			So(skin.RenderHeadSVG(buf, 1, true), ShouldBeNil)
+
+			So(buf.String(), ShouldEqual, `<svg xmlns="http://www.w3.org/2000/svg" width="8" height="8" viewBox="0 0 8 8" shape-rendering="crispEdges">
+<rect x="0" y="0" width="1" height="1" fill="#ffffff" fill-opacity="0.502"/>
+<rect x="1" y="0" width="7" height="8" fill="#007864"/>
+<rect x="0" y="1" width="1" height="7" fill="#007864"/>
+</svg>
+`)
+		})
+
+		Convey("Steve's body should be valid XML with fewer rectangles than pixels", func() {
+			steve, _ := FetchSkinForSteve()
+			buf := &bytes.Buffer{}
+			So(steve.RenderBodySVG(buf, 4, true), ShouldBeNil)
+
+			var svg struct {
+				Width string `xml:"width,attr"`
+				Rects []struct {
+					Fill string `xml:"fill,attr"`
+				} `xml:"rect"`
+			}
+			So(xml.Unmarshal(buf.Bytes(), &svg), ShouldBeNil)
+			So(svg.Width, ShouldEqual, "64")
+			So(len(svg.Rects), ShouldBeGreaterThan, 0)
+			So(len(svg.Rects), ShouldBeLessThan, 16*32)
+		})
+
+		Convey("Bad scales should gracefully fail", func() {
+			steve, _ := FetchSkinForSteve()
+			err := steve.RenderBodyBackSVG(&bytes.Buffer{}, 0, true)
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to RenderBodyBackSVG: scale must be at least 1 (got 0)")
+		})
+
+	})
+
+}
+
+func TestRenderSpin(t *testing.T) {
+
+	Convey("Test Skin.RenderHeadSpin and Skin.RenderBodySpin", t, func() {
+
+		Convey("Steve's head should make a looping turn", func() {
+			steve, _ := FetchSkinForSteve()
+			anim, err := steve.RenderHeadSpin(SpinOptions{IsoOptions: IsoOptions{Size: 32, Overlay: true}})
+
+			So(err, ShouldBeNil)
+			So(anim.Image, ShouldHaveLength, 24)
+			So(anim.Delay[0], ShouldEqual, 8)
+			So(anim.LoopCount, ShouldEqual, 0)
+			So(anim.Image[0].Bounds(), ShouldResemble, image.Rect(0, 0, 32, 32))
+
+			// The first frame is the standard view
+			still, _ := steve.RenderHeadIso(32, true)
+			So(sameVisiblePixels(anim.Image[0], palettize(still, nil)), ShouldBeTrue)
+
+			// Half way round we see the back of the head
+			back, _ := steve.RenderHeadIsoWithOptions(IsoOptions{Size: 32, Overlay: true, Yaw: 180})
+			So(sameVisiblePixels(anim.Image[12], palettize(back, nil)), ShouldBeTrue)
+
+			buf := &bytes.Buffer{}
+			So(gif.EncodeAll(buf, anim), ShouldBeNil)
+			decoded, err := gif.DecodeAll(buf)
+			So(err, ShouldBeNil)
+			So(decoded.Image, ShouldHaveLength, 24)
+		})
+
+		Convey("Bodies should honour the options", func() {
+			steve, _ := FetchSkinForSteve()
+			anim, err := steve.RenderBodySpin(SpinOptions{
+				IsoOptions: IsoOptions{Size: 64},
+				Frames:     4,
+				Delay:      25,
+				Background: color.White,
+			})
+
+			So(err, ShouldBeNil)
+			So(anim.Image, ShouldHaveLength, 4)
+			So(anim.Delay, ShouldResemble, []int{25, 25, 25, 25})
+			So(anim.Image[0].Bounds(), ShouldResemble, image.Rect(0, 0, 32, 64))
+			// Flattened onto the background
+			So(color.NRGBAModel.Convert(anim.Image[0].At(0, 0)), ShouldResemble, color.NRGBA{255, 255, 255, 255})
+		})
+
+		Convey("Bad options should gracefully fail", func() {
+			steve, _ := FetchSkinForSteve()
+
+			_, err := steve.RenderHeadSpin(SpinOptions{IsoOptions: IsoOptions{Size: 32}, Frames: -1})
+			So(err.Error(), ShouldEqual, "unable to RenderHeadSpin: frames must be between 1 and 360 (got -1)")
+
+			_, err = steve.RenderBodySpin(SpinOptions{IsoOptions: IsoOptions{Size: 1}})
+			So(err.Error(), ShouldEqual, "unable to RenderBodySpin: unable to RenderBodyIso: size must be at least 2 (got 1)")
+		})
+
+	})
+
+}
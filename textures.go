@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	// If we work with PNGs we need this
 	_ "image/png"
+	"time"
 
 	"github.com/pkg/errors"
 )
@@ -28,6 +29,11 @@ type SessionProfileTextureProperty struct {
 	} `json:"textures"`
 }
 
+// Time returns when the textures property was created
+func (p SessionProfileTextureProperty) Time() time.Time {
+	return time.Unix(0, int64(p.TimestampMs)*int64(time.Millisecond))
+}
+
 // DecodeTextureProperty takes a SessionProfileResponse and breaks it down into the Skin/Cape URLs for downloading them
 func DecodeTextureProperty(sessionProfile SessionProfileResponse) (SessionProfileTextureProperty, error) {
 	var texturesProperty *SessionProfileProperty
@@ -1,25 +1,64 @@
 package minecraft
 
-import _ "image/png" // If we work with PNGs we need this
+import (
+	"context"
+
+	// If we work with PNGs we need this
+	_ "image/png"
+
+	"github.com/pkg/errors"
+)
 
 type Cape struct {
 	Texture
 }
 
 func (mc *Minecraft) FetchCapeUUID(uuid string) (Cape, error) {
+	return mc.fetchCapeUUID(context.Background(), uuid)
+}
+
+func (mc *Minecraft) fetchCapeUUID(ctx context.Context, uuid string) (Cape, error) {
 	cape := &Cape{Texture{Mc: mc}}
 
 	// Must be careful to not request same profile from session server more than once per ~30 seconds
-	sessionProfile, err := mc.GetSessionProfile(uuid)
+	sessionProfile, err := mc.getSessionProfileContext(ctx, uuid)
 	if err != nil {
 		return *cape, err
 	}
 
-	return *cape, cape.FetchWithSessionProfile(sessionProfile, "Cape")
+	err = cape.fetchWithSessionProfile(ctx, sessionProfile, "Cape")
+	return *cape, err
 }
 
 func (mc *Minecraft) FetchCapeUsername(username string) (Cape, error) {
+	return mc.fetchCapeUsername(context.Background(), username)
+}
+
+func (mc *Minecraft) fetchCapeUsername(ctx context.Context, username string) (Cape, error) {
 	cape := &Cape{Texture{Mc: mc}}
 
-	return *cape, cape.FetchWithUsername(username, "Cape")
+	err := cape.fetchWithUsername(ctx, username, "Cape")
+	return *cape, err
+}
+
+// FetchCapePlayer takes a Username or UUID and fetches their cape, using the
+// UsernameAPI for usernames when it is set and the UUIDAPI otherwise
+func (mc *Minecraft) FetchCapePlayer(player string) (Cape, error) {
+	return mc.FetchCapePlayerContext(context.Background(), player)
+}
+
+// FetchCapePlayerContext is FetchCapePlayer, giving up on the requests once ctx
+// is done
+func (mc *Minecraft) FetchCapePlayerContext(ctx context.Context, player string) (Cape, error) {
+	if RegexUsername.MatchString(player) && mc.UsernameAPI.CapeURL != "" {
+		return mc.fetchCapeUsername(ctx, player)
+	}
+
+	uuid, stale, err := mc.normalizePlayerForUUID(ctx, player)
+	if err != nil {
+		return Cape{Texture{Mc: mc}}, errors.Wrap(err, "unable to FetchCapePlayer")
+	}
+	cape, err := mc.fetchCapeUUID(ctx, uuid)
+	cape.Stale = cape.Stale || stale
+	return cape, err
 }
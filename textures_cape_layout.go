@@ -0,0 +1,64 @@
+package minecraft
+
+import (
+	"github.com/pkg/errors"
+)
+
+var (
+	// capeLayout is where the cape is laid out. Its front face is the outside
+	// of the cape, as seen from behind the player.
+	capeLayout = SkinBox{Name: "cape", U: 0, V: 0, W: 10, H: 16, D: 1}
+
+	// elytraLayout is where the left wing of the elytra is laid out (the right
+	// wing is a mirror of it). Its front face is the outside of the wing, as
+	// seen from behind the player.
+	elytraLayout = SkinBox{Name: "elytra", U: 22, V: 0, W: 10, H: 20, D: 2}
+)
+
+// IsLegacy reports whether the cape uses the old 22x17 layout, which has no
+// room for an elytra
+func (c *Cape) IsLegacy() bool {
+	if c.Image == nil {
+		return false
+	}
+	bounds := c.Image.Bounds()
+	return bounds.Dx()%22 == 0 && bounds.Dx()*17 == bounds.Dy()*22
+}
+
+// layoutUnit returns how many image pixels make up a texture pixel
+func (c *Cape) layoutUnit() (int, error) {
+	if c.Image == nil {
+		return 0, errors.New("cape has no image")
+	}
+
+	bounds := c.Image.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	switch {
+	case width > 0 && width%64 == 0 && height*2 == width:
+		return width / 64, nil
+	case c.IsLegacy() && width > 0:
+		return width / 22, nil
+	}
+	return 0, errors.Errorf("unsupported cape dimensions %dx%d", width, height)
+}
+
+// CapeBox returns the texture layout of the cape
+func (c *Cape) CapeBox() (SkinBox, error) {
+	unit, err := c.layoutUnit()
+	if err != nil {
+		return SkinBox{}, err
+	}
+	return capeLayout.scaled(unit), nil
+}
+
+// ElytraBox returns the texture layout of the elytra's left wing
+func (c *Cape) ElytraBox() (SkinBox, error) {
+	unit, err := c.layoutUnit()
+	if err != nil {
+		return SkinBox{}, err
+	}
+	if c.IsLegacy() {
+		return SkinBox{}, errors.New("legacy capes have no elytra texture")
+	}
+	return elytraLayout.scaled(unit), nil
+}
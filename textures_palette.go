@@ -0,0 +1,187 @@
+package minecraft
+
+import (
+	"image"
+	"image/color"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// PaletteColor is one of the dominant colours of a skin
+type PaletteColor struct {
+	Color color.NRGBA
+	// Weight is the share of the visible pixels closest to this colour (0 to 1)
+	Weight float64
+}
+
+// colorCount is a colour and how many pixels have it
+type colorCount struct {
+	color color.NRGBA
+	count int
+}
+
+// Palette returns up to n dominant colours of the skin, heaviest first, found
+// by median cut over the visible pixels of the given parts (or every part).
+// Transparent pixels and overlay pixels of the background matte are ignored.
+func (s *Skin) Palette(n int, parts ...SkinPart) ([]PaletteColor, error) {
+	if n < 1 {
+		return nil, errors.Errorf("unable to Palette: colours must be at least 1 (got %d)", n)
+	}
+
+	// Leave out overlays which are nothing but the matte
+	clean, _, err := s.SanitizeOverlay()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to Palette")
+	}
+	if len(parts) == 0 {
+		parts = SkinParts
+	}
+
+	img := clean.Image.(*image.NRGBA)
+	matte := s.matte()
+	counts := make(map[color.NRGBA]int)
+	for _, box := range clean.Boxes() {
+		if !containsPart(parts, box.Part) {
+			continue
+		}
+		for _, face := range BoxFaces {
+			rect := box.Face(face)
+			for y := rect.Min.Y; y < rect.Max.Y; y++ {
+				for x := rect.Min.X; x < rect.Max.X; x++ {
+					c := img.NRGBAAt(x, y)
+					if c.A < 0x80 || (box.Layer == LayerOverlay && matte != nil && c == *matte) {
+						continue
+					}
+					c.A = 0xff
+					counts[c]++
+				}
+			}
+		}
+	}
+
+	var total int
+	colours := make([]colorCount, 0, len(counts))
+	for c, count := range counts {
+		colours = append(colours, colorCount{c, count})
+		total += count
+	}
+	if total == 0 {
+		return nil, nil
+	}
+
+	var palette []PaletteColor
+	for _, bucket := range medianCut(colours, n) {
+		c, count := averageColor(bucket)
+		palette = append(palette, PaletteColor{Color: c, Weight: float64(count) / float64(total)})
+	}
+	sort.SliceStable(palette, func(i, j int) bool {
+		return palette[i].Weight > palette[j].Weight
+	})
+	return palette, nil
+}
+
+// medianCut splits the colours into up to n buckets, each time cutting the
+// most used bucket at the median of its widest channel
+func medianCut(colours []colorCount, n int) [][]colorCount {
+	// Start from a known order, as map iteration is random
+	sort.Slice(colours, func(i, j int) bool {
+		a, b := colours[i].color, colours[j].color
+		if a.R != b.R {
+			return a.R < b.R
+		}
+		if a.G != b.G {
+			return a.G < b.G
+		}
+		return a.B < b.B
+	})
+
+	buckets := [][]colorCount{colours}
+	for len(buckets) < n {
+		split, most := -1, 0
+		for i, bucket := range buckets {
+			if len(bucket) < 2 {
+				continue
+			}
+			if count := bucketCount(bucket); count > most {
+				split, most = i, count
+			}
+		}
+		if split < 0 {
+			// Every bucket is down to a single colour
+			break
+		}
+
+		bucket := buckets[split]
+		channel := widestChannel(bucket)
+		sort.SliceStable(bucket, func(i, j int) bool {
+			return channel(bucket[i].color) < channel(bucket[j].color)
+		})
+
+		// Cut where half the pixels are on each side, keeping both halves
+		// non-empty
+		cut, seen := 1, bucket[0].count
+		for cut < len(bucket)-1 && seen*2 < most {
+			seen += bucket[cut].count
+			cut++
+		}
+
+		buckets[split] = bucket[:cut]
+		buckets = append(buckets, bucket[cut:])
+	}
+	return buckets
+}
+
+// widestChannel returns the channel with the greatest range in the bucket
+func widestChannel(bucket []colorCount) func(color.NRGBA) uint8 {
+	channels := []func(color.NRGBA) uint8{
+		func(c color.NRGBA) uint8 { return c.R },
+		func(c color.NRGBA) uint8 { return c.G },
+		func(c color.NRGBA) uint8 { return c.B },
+	}
+
+	var widest func(color.NRGBA) uint8
+	widestRange := -1
+	for _, channel := range channels {
+		min, max := 255, 0
+		for _, cc := range bucket {
+			v := int(channel(cc.color))
+			min, max = minInt(min, v), maxInt(max, v)
+		}
+		if max-min > widestRange {
+			widest, widestRange = channel, max-min
+		}
+	}
+	return widest
+}
+
+// bucketCount is the number of pixels in the bucket
+func bucketCount(bucket []colorCount) int {
+	var count int
+	for _, cc := range bucket {
+		count += cc.count
+	}
+	return count
+}
+
+// averageColor is the mean colour of the pixels in the bucket
+func averageColor(bucket []colorCount) (color.NRGBA, int) {
+	var r, g, b, count int
+	for _, cc := range bucket {
+		r += int(cc.color.R) * cc.count
+		g += int(cc.color.G) * cc.count
+		b += int(cc.color.B) * cc.count
+		count += cc.count
+	}
+	return color.NRGBA{uint8((r + count/2) / count), uint8((g + count/2) / count), uint8((b + count/2) / count), 0xff}, count
+}
+
+// containsPart reports whether the part is in the list
+func containsPart(parts []SkinPart, part SkinPart) bool {
+	for _, p := range parts {
+		if p == part {
+			return true
+		}
+	}
+	return false
+}
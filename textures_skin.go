@@ -1,25 +1,64 @@
 package minecraft
 
-import _ "image/png" // If we work with PNGs we need this
+import (
+	"context"
+
+	// If we work with PNGs we need this
+	_ "image/png"
+
+	"github.com/pkg/errors"
+)
 
 type Skin struct {
 	Texture
 }
 
 func (mc *Minecraft) FetchSkinUUID(uuid string) (Skin, error) {
+	return mc.fetchSkinUUID(context.Background(), uuid)
+}
+
+func (mc *Minecraft) fetchSkinUUID(ctx context.Context, uuid string) (Skin, error) {
 	skin := &Skin{Texture{Mc: mc}}
 
 	// Must be careful to not request same profile from session server more than once per ~30 seconds
-	sessionProfile, err := mc.GetSessionProfile(uuid)
+	sessionProfile, err := mc.getSessionProfileContext(ctx, uuid)
 	if err != nil {
 		return *skin, err
 	}
 
-	return *skin, skin.FetchWithSessionProfile(sessionProfile, "Skin")
+	err = skin.fetchWithSessionProfile(ctx, sessionProfile, "Skin")
+	return *skin, err
 }
 
 func (mc *Minecraft) FetchSkinUsername(username string) (Skin, error) {
+	return mc.fetchSkinUsername(context.Background(), username)
+}
+
+func (mc *Minecraft) fetchSkinUsername(ctx context.Context, username string) (Skin, error) {
 	skin := &Skin{Texture{Mc: mc}}
 
-	return *skin, skin.FetchWithUsername(username, "Skin")
+	err := skin.fetchWithUsername(ctx, username, "Skin")
+	return *skin, err
+}
+
+// FetchSkinPlayer takes a Username or UUID and fetches their skin, using the
+// UsernameAPI for usernames when it is set and the UUIDAPI otherwise
+func (mc *Minecraft) FetchSkinPlayer(player string) (Skin, error) {
+	return mc.FetchSkinPlayerContext(context.Background(), player)
+}
+
+// FetchSkinPlayerContext is FetchSkinPlayer, giving up on the requests once ctx
+// is done
+func (mc *Minecraft) FetchSkinPlayerContext(ctx context.Context, player string) (Skin, error) {
+	if RegexUsername.MatchString(player) && mc.UsernameAPI.SkinURL != "" {
+		return mc.fetchSkinUsername(ctx, player)
+	}
+
+	uuid, stale, err := mc.normalizePlayerForUUID(ctx, player)
+	if err != nil {
+		return Skin{Texture{Mc: mc}}, errors.Wrap(err, "unable to FetchSkinPlayer")
+	}
+	skin, err := mc.fetchSkinUUID(ctx, uuid)
+	skin.Stale = skin.Stale || stale
+	return skin, err
 }
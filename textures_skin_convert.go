@@ -0,0 +1,119 @@
+package minecraft
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/pkg/errors"
+)
+
+// ArmStrategy chooses which column of the arms ConvertModel removes (going
+// from classic to slim) or duplicates (going from slim to classic)
+type ArmStrategy int
+
+const (
+	// ArmInner changes the column next to the body
+	ArmInner ArmStrategy = iota
+	// ArmOuter changes the column furthest from the body
+	ArmOuter
+	// ArmMiddle changes a column in the middle of the arm (for classic arms,
+	// the one of the middle two nearer the body)
+	ArmMiddle
+)
+
+// ConvertModel returns a copy of the skin converted to another arm model
+// (SkinModelClassic or SkinModelSlim), squashing or stretching both layers
+// of the arms to fit. A skin that already has the model is returned as it is.
+func (s *Skin) ConvertModel(model string, strategy ArmStrategy) (Skin, error) {
+	unit, err := s.layoutUnit()
+	if err != nil {
+		return Skin{}, errors.Wrap(err, "unable to ConvertModel")
+	}
+	if model != SkinModelClassic && model != SkinModelSlim {
+		return Skin{}, errors.Errorf("unable to ConvertModel: unknown model %q", model)
+	}
+	if strategy < ArmInner || strategy > ArmMiddle {
+		return Skin{}, errors.Errorf("unable to ConvertModel: unknown strategy %d", strategy)
+	}
+	if s.IsLegacy() {
+		return Skin{}, errors.New("unable to ConvertModel: legacy skins only have classic arms")
+	}
+
+	if (model == SkinModelSlim) == s.IsSlim() {
+		// Already the model asked for, so there's nothing to change
+		return *s, nil
+	}
+
+	converted := s.clone()
+	converted.Model = model
+
+	img := converted.Image.(*image.NRGBA)
+	for _, part := range []SkinPart{PartRightArm, PartLeftArm} {
+		for _, layer := range []SkinLayer{LayerBase, LayerOverlay} {
+			from, _ := s.Box(part, layer)
+			to, _ := converted.Box(part, layer)
+
+			faces := make(map[BoxFace]*image.NRGBA)
+			for _, face := range BoxFaces {
+				faces[face] = faceImage(s.Image, from, face)
+			}
+			clearRect(img, from.Area())
+
+			for _, face := range BoxFaces {
+				src := faces[face]
+				if face != FaceRight && face != FaceLeft {
+					src = pickColumns(src, unit, armColumns(part, face, from.W/unit, to.W/unit, strategy))
+				}
+				draw.Draw(img, to.Face(face), src, image.Point{}, draw.Src)
+			}
+		}
+	}
+
+	converted.sign()
+	return converted, nil
+}
+
+// armColumns lists which of the from columns of an arm face make up each of
+// the to columns, removing or duplicating one as the strategy says
+func armColumns(part SkinPart, face BoxFace, from, to int, strategy ArmStrategy) []int {
+	// Count the column to change from the outside of the arm
+	var changed int
+	switch strategy {
+	case ArmInner:
+		changed = from - 1
+	case ArmOuter:
+		changed = 0
+	case ArmMiddle:
+		changed = from / 2
+	}
+
+	// The faces run across the player from their right to their left, apart
+	// from the back which runs the other way. So the outside of the right
+	// arm is the first column, unless we are looking at its back.
+	if (part == PartRightArm) == (face == FaceBack) {
+		changed = from - 1 - changed
+	}
+
+	columns := make([]int, 0, to)
+	for column := 0; column < from; column++ {
+		if column == changed {
+			if to < from {
+				continue
+			}
+			columns = append(columns, column)
+		}
+		columns = append(columns, column)
+	}
+	return columns
+}
+
+// pickColumns builds a new image from columns (unit pixels wide) of another
+func pickColumns(img *image.NRGBA, unit int, columns []int) *image.NRGBA {
+	bounds := img.Bounds()
+	picked := image.NewNRGBA(image.Rect(0, 0, len(columns)*unit, bounds.Dy()))
+	for i, column := range columns {
+		dst := image.Rect(i*unit, 0, (i+1)*unit, bounds.Dy())
+		draw.Draw(picked, dst, img, bounds.Min.Add(image.Pt(column*unit, 0)), draw.Src)
+	}
+	return picked
+}
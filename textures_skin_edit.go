@@ -0,0 +1,273 @@
+package minecraft
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// oppositeLimb pairs each limb with the one on the other side of the player
+var oppositeLimb = map[SkinPart]SkinPart{
+	PartRightArm: PartLeftArm,
+	PartLeftArm:  PartRightArm,
+	PartRightLeg: PartLeftLeg,
+	PartLeftLeg:  PartRightLeg,
+}
+
+// region finds a named area of the skin: either a whole box (eg. "hat") or a
+// face of one (eg. "hat.front"). The bool is true when the region is a face.
+func (s *Skin) region(name string) (SkinBox, BoxFace, bool, error) {
+	boxName, faceName := name, ""
+	if i := strings.Index(name, "."); i >= 0 {
+		boxName, faceName = name[:i], name[i+1:]
+	}
+
+	for _, box := range s.Boxes() {
+		if box.Name != boxName {
+			continue
+		}
+		if faceName == "" {
+			return box, 0, false, nil
+		}
+		for _, face := range BoxFaces {
+			if face.String() == faceName {
+				return box, face, true, nil
+			}
+		}
+	}
+	return SkinBox{}, 0, false, errors.Errorf("unknown region %q", name)
+}
+
+// regionRect returns the area of the texture used by a named region
+func (s *Skin) regionRect(name string) (image.Rectangle, error) {
+	box, face, isFace, err := s.region(name)
+	if err != nil {
+		return image.Rectangle{}, err
+	}
+	if isFace {
+		return box.Face(face), nil
+	}
+	return box.Area(), nil
+}
+
+// Paste returns a copy of the skin with the image pasted over a region, eg.
+// "head" or "hat.front", replacing what was there (including transparency).
+// The image is resized to fit the region.
+func (s *Skin) Paste(region string, img image.Image) (Skin, error) {
+	if _, err := s.layoutUnit(); err != nil {
+		return Skin{}, errors.Wrap(err, "unable to Paste")
+	}
+	box, face, isFace, err := s.region(region)
+	if err != nil {
+		return Skin{}, errors.Wrap(err, "unable to Paste")
+	}
+
+	rect := box.Area()
+	if isFace {
+		rect = box.Face(face)
+	} else if box.Mirror {
+		// The faces of a mirrored box can't be flipped as one image
+		return Skin{}, errors.Errorf("unable to Paste: %s is mirrored, paste its faces instead", box.Name)
+	}
+
+	src := resizeNearest(toNRGBA(img), rect.Dx(), rect.Dy())
+	if box.Mirror {
+		src = flipHorizontal(src)
+	}
+
+	edited := s.clone()
+	draw.Draw(edited.Image.(*image.NRGBA), rect, src, src.Rect.Min, draw.Src)
+	edited.sign()
+	return edited, nil
+}
+
+// Recolor returns a copy of the skin with every visible pixel in the regions
+// passed through fn. With no regions, the whole skin is recoloured.
+func (s *Skin) Recolor(fn func(color.NRGBA) color.NRGBA, regions ...string) (Skin, error) {
+	if _, err := s.layoutUnit(); err != nil {
+		return Skin{}, errors.Wrap(err, "unable to Recolor")
+	}
+
+	edited := s.clone()
+	img := edited.Image.(*image.NRGBA)
+
+	rects := []image.Rectangle{img.Rect}
+	if len(regions) > 0 {
+		rects = rects[:0]
+		for _, region := range regions {
+			rect, err := s.regionRect(region)
+			if err != nil {
+				return Skin{}, errors.Wrap(err, "unable to Recolor")
+			}
+			rects = append(rects, rect)
+		}
+	}
+
+	// Regions can overlap (eg. "hat" and "hat.front"), but each pixel
+	// should only be recoloured once
+	done := make([]bool, len(img.Pix)/4)
+	for _, rect := range rects {
+		for y := rect.Min.Y; y < rect.Max.Y; y++ {
+			for x := rect.Min.X; x < rect.Max.X; x++ {
+				i := img.PixOffset(x, y) / 4
+				if done[i] {
+					continue
+				}
+				done[i] = true
+
+				// Blank areas are left blank
+				if c := img.NRGBAAt(x, y); c.A != 0 {
+					img.SetNRGBA(x, y, fn(c))
+				}
+			}
+		}
+	}
+	edited.sign()
+	return edited, nil
+}
+
+// HueShift returns a copy of the skin with the hue of the regions turned by
+// degrees. With no regions, the whole skin is shifted.
+func (s *Skin) HueShift(degrees float64, regions ...string) (Skin, error) {
+	edited, err := s.Recolor(func(c color.NRGBA) color.NRGBA {
+		h, sat, v := rgbToHSV(c)
+		return hsvToRGB(math.Mod(h+degrees, 360), sat, v, c.A)
+	}, regions...)
+	if err != nil {
+		return Skin{}, errors.Wrap(err, "unable to HueShift")
+	}
+	return edited, nil
+}
+
+// FlattenOverlay returns a copy of the skin with each overlay drawn onto its
+// base layer and then cleared
+func (s *Skin) FlattenOverlay() (Skin, error) {
+	if _, err := s.layoutUnit(); err != nil {
+		return Skin{}, errors.Wrap(err, "unable to FlattenOverlay")
+	}
+
+	edited := s.clone()
+	img := edited.Image.(*image.NRGBA)
+	for _, part := range SkinParts {
+		overlay, ok := s.Box(part, LayerOverlay)
+		if !ok {
+			continue
+		}
+		base, _ := s.Box(part, LayerBase)
+
+		for _, face := range BoxFaces {
+			draw.Draw(img, base.Face(face), faceImage(img, overlay, face), image.Point{}, draw.Over)
+		}
+		clearRect(img, overlay.Area())
+	}
+	edited.sign()
+	return edited, nil
+}
+
+// MirrorLimbs returns a copy of the skin with the left arm and leg replaced by
+// flipped copies of the right ones, as legacy skins are drawn
+func (s *Skin) MirrorLimbs() (Skin, error) {
+	edited, err := s.MirrorLimb(PartRightArm, PartLeftArm)
+	if err != nil {
+		return Skin{}, errors.Wrap(err, "unable to MirrorLimbs")
+	}
+	edited, err = edited.MirrorLimb(PartRightLeg, PartLeftLeg)
+	if err != nil {
+		return Skin{}, errors.Wrap(err, "unable to MirrorLimbs")
+	}
+	return edited, nil
+}
+
+// MirrorLimb returns a copy of the skin with a limb replaced by a flipped copy
+// of the opposite one (both layers), eg. PartRightArm onto PartLeftArm
+func (s *Skin) MirrorLimb(from, to SkinPart) (Skin, error) {
+	if _, err := s.layoutUnit(); err != nil {
+		return Skin{}, errors.Wrap(err, "unable to MirrorLimb")
+	}
+	if opposite, ok := oppositeLimb[from]; !ok || opposite != to {
+		return Skin{}, errors.Errorf("unable to MirrorLimb: %s is not the opposite of %s", to, from)
+	}
+	if s.IsLegacy() {
+		return Skin{}, errors.New("unable to MirrorLimb: legacy skins always mirror their limbs")
+	}
+
+	edited := s.clone()
+	img := edited.Image.(*image.NRGBA)
+	for _, layer := range []SkinLayer{LayerBase, LayerOverlay} {
+		src, _ := s.Box(from, layer)
+		dst, _ := s.Box(to, layer)
+
+		// Reading the source as a mirror of the destination flips each face
+		// and swaps the sides over
+		mirrored := mirroredBox(src, dst)
+		for _, face := range BoxFaces {
+			draw.Draw(img, dst.Face(face), faceImage(s.Image, mirrored, face), image.Point{}, draw.Src)
+		}
+	}
+	edited.sign()
+	return edited, nil
+}
+
+// rgbToHSV returns the hue (0-360), saturation and value (0-1) of the colour
+func rgbToHSV(c color.NRGBA) (float64, float64, float64) {
+	r, g, b := float64(c.R)/0xff, float64(c.G)/0xff, float64(c.B)/0xff
+	max := math.Max(r, math.Max(g, b))
+	min := math.Min(r, math.Min(g, b))
+	delta := max - min
+
+	var h float64
+	switch {
+	case delta == 0:
+		h = 0
+	case max == r:
+		h = 60 * math.Mod((g-b)/delta, 6)
+	case max == g:
+		h = 60 * ((b-r)/delta + 2)
+	default:
+		h = 60 * ((r-g)/delta + 4)
+	}
+	if h < 0 {
+		h += 360
+	}
+
+	var sat float64
+	if max > 0 {
+		sat = delta / max
+	}
+	return h, sat, max
+}
+
+// hsvToRGB is the inverse of rgbToHSV
+func hsvToRGB(h, sat, v float64, a uint8) color.NRGBA {
+	if h < 0 {
+		h += 360
+	}
+	chroma := v * sat
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := v - chroma
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = chroma, x, 0
+	case h < 120:
+		r, g, b = x, chroma, 0
+	case h < 180:
+		r, g, b = 0, chroma, x
+	case h < 240:
+		r, g, b = 0, x, chroma
+	case h < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+
+	channel := func(f float64) uint8 {
+		return uint8(math.Round((f + m) * 0xff))
+	}
+	return color.NRGBA{channel(r), channel(g), channel(b), a}
+}
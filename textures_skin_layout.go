@@ -0,0 +1,246 @@
+package minecraft
+
+import (
+	"image"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// SkinModelClassic is the model metadata for the wide armed "Steve" model (Mojang omit it)
+	SkinModelClassic = ""
+	// SkinModelSlim is the model metadata for the slim armed "Alex" model
+	SkinModelSlim = "slim"
+)
+
+// BoxFace is one of the six faces of a SkinBox
+type BoxFace int
+
+const (
+	FaceTop BoxFace = iota
+	FaceBottom
+	FaceRight
+	FaceFront
+	FaceLeft
+	FaceBack
+)
+
+// BoxFaces lists every face in the order they are laid out on the texture
+var BoxFaces = []BoxFace{FaceTop, FaceBottom, FaceRight, FaceFront, FaceLeft, FaceBack}
+
+var boxFaceNames = [...]string{"top", "bottom", "right", "front", "left", "back"}
+
+func (f BoxFace) String() string {
+	if f < 0 || int(f) >= len(boxFaceNames) {
+		return "unknown"
+	}
+	return boxFaceNames[f]
+}
+
+// SkinPart is a part of the player model
+type SkinPart int
+
+const (
+	PartHead SkinPart = iota
+	PartBody
+	PartRightArm
+	PartLeftArm
+	PartRightLeg
+	PartLeftLeg
+)
+
+// SkinParts lists every part of the player model
+var SkinParts = []SkinPart{PartHead, PartBody, PartRightArm, PartLeftArm, PartRightLeg, PartLeftLeg}
+
+var skinPartNames = [...]string{"head", "body", "right_arm", "left_arm", "right_leg", "left_leg"}
+
+func (p SkinPart) String() string {
+	if p < 0 || int(p) >= len(skinPartNames) {
+		return "unknown"
+	}
+	return skinPartNames[p]
+}
+
+// SkinLayer is either the base layer of a part or the overlay (hat, jacket, sleeves and pants) drawn over it
+type SkinLayer int
+
+const (
+	LayerBase SkinLayer = iota
+	LayerOverlay
+)
+
+// SkinBox describes where a cuboid of the player model is laid out on a texture
+type SkinBox struct {
+	// Name of the box, eg. "head" or "hat"
+	Name string
+	Part SkinPart
+	// Layer the box belongs to
+	Layer SkinLayer
+	// U and V are the top-left corner of the box layout on the texture
+	U, V int
+	// W, H and D are the width, height and depth of the cuboid in texture pixels
+	W, H, D int
+	// Mirror is set when the box borrows the (flipped) texture of the opposite limb, as legacy skins do
+	Mirror bool
+}
+
+// Face returns the area of the texture used for a face of the box. When the box
+// is mirrored, the returned area must be flipped horizontally before use.
+func (b SkinBox) Face(f BoxFace) image.Rectangle {
+	if b.Mirror {
+		// The sides swap over along with the flip
+		if f == FaceRight {
+			f = FaceLeft
+		} else if f == FaceLeft {
+			f = FaceRight
+		}
+	}
+
+	u, v, w, h, d := b.U, b.V, b.W, b.H, b.D
+	switch f {
+	case FaceTop:
+		return image.Rect(u+d, v, u+d+w, v+d)
+	case FaceBottom:
+		return image.Rect(u+d+w, v, u+d+w+w, v+d)
+	case FaceRight:
+		return image.Rect(u, v+d, u+d, v+d+h)
+	case FaceFront:
+		return image.Rect(u+d, v+d, u+d+w, v+d+h)
+	case FaceLeft:
+		return image.Rect(u+d+w, v+d, u+d+w+d, v+d+h)
+	case FaceBack:
+		return image.Rect(u+d+w+d, v+d, u+d+w+d+w, v+d+h)
+	}
+	return image.Rectangle{}
+}
+
+// Area returns the whole area of the texture the box is laid out in,
+// including the unused corners beside the top and bottom faces
+func (b SkinBox) Area() image.Rectangle {
+	return image.Rect(b.U, b.V, b.U+(b.W+b.D)*2, b.V+b.D+b.H)
+}
+
+// FaceName names a face of the box, eg. "hat.front"
+func (b SkinBox) FaceName(f BoxFace) string {
+	return b.Name + "." + f.String()
+}
+
+// scaled multiplies the box layout for high resolution textures
+func (b SkinBox) scaled(unit int) SkinBox {
+	b.U, b.V, b.W, b.H, b.D = b.U*unit, b.V*unit, b.W*unit, b.H*unit, b.D*unit
+	return b
+}
+
+// skinLayout is the 64x64 layout of the classic model, indexed by part and layer
+var skinLayout = map[SkinPart][2]SkinBox{
+	PartHead: {
+		{Name: "head", Part: PartHead, Layer: LayerBase, U: 0, V: 0, W: 8, H: 8, D: 8},
+		{Name: "hat", Part: PartHead, Layer: LayerOverlay, U: 32, V: 0, W: 8, H: 8, D: 8},
+	},
+	PartBody: {
+		{Name: "body", Part: PartBody, Layer: LayerBase, U: 16, V: 16, W: 8, H: 12, D: 4},
+		{Name: "jacket", Part: PartBody, Layer: LayerOverlay, U: 16, V: 32, W: 8, H: 12, D: 4},
+	},
+	PartRightArm: {
+		{Name: "right_arm", Part: PartRightArm, Layer: LayerBase, U: 40, V: 16, W: 4, H: 12, D: 4},
+		{Name: "right_sleeve", Part: PartRightArm, Layer: LayerOverlay, U: 40, V: 32, W: 4, H: 12, D: 4},
+	},
+	PartLeftArm: {
+		{Name: "left_arm", Part: PartLeftArm, Layer: LayerBase, U: 32, V: 48, W: 4, H: 12, D: 4},
+		{Name: "left_sleeve", Part: PartLeftArm, Layer: LayerOverlay, U: 48, V: 48, W: 4, H: 12, D: 4},
+	},
+	PartRightLeg: {
+		{Name: "right_leg", Part: PartRightLeg, Layer: LayerBase, U: 0, V: 16, W: 4, H: 12, D: 4},
+		{Name: "right_pants", Part: PartRightLeg, Layer: LayerOverlay, U: 0, V: 32, W: 4, H: 12, D: 4},
+	},
+	PartLeftLeg: {
+		{Name: "left_leg", Part: PartLeftLeg, Layer: LayerBase, U: 16, V: 48, W: 4, H: 12, D: 4},
+		{Name: "left_pants", Part: PartLeftLeg, Layer: LayerOverlay, U: 0, V: 48, W: 4, H: 12, D: 4},
+	},
+}
+
+// IsSlim reports whether the skin uses the slim armed model
+func (s *Skin) IsSlim() bool {
+	return s.Model == SkinModelSlim
+}
+
+// IsLegacy reports whether the skin uses the pre-1.8 64x32 layout (no overlays
+// besides the hat, with the left limbs mirroring the right ones)
+func (s *Skin) IsLegacy() bool {
+	if s.Image == nil {
+		return false
+	}
+	bounds := s.Image.Bounds()
+	return bounds.Dy()*2 == bounds.Dx()
+}
+
+// layoutUnit returns how many image pixels make up a texture pixel (1 for
+// 64px wide skins, 2 for 128px wide HD skins etc.)
+func (s *Skin) layoutUnit() (int, error) {
+	if s.Image == nil {
+		return 0, errors.New("skin has no image")
+	}
+
+	bounds := s.Image.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || width%64 != 0 || (height != width && height*2 != width) {
+		return 0, errors.Errorf("unsupported skin dimensions %dx%d", width, height)
+	}
+	return width / 64, nil
+}
+
+// Box returns the texture layout for a layer of a part, accounting for the
+// slim model, legacy skins and high resolution skins. The bool is false when
+// the skin has no such box (eg. the jacket of a legacy skin).
+func (s *Skin) Box(part SkinPart, layer SkinLayer) (SkinBox, bool) {
+	unit, err := s.layoutUnit()
+	if err != nil {
+		return SkinBox{}, false
+	}
+
+	boxes, ok := skinLayout[part]
+	if !ok || layer < LayerBase || layer > LayerOverlay {
+		return SkinBox{}, false
+	}
+	box := boxes[layer]
+
+	if s.IsLegacy() {
+		switch {
+		case part == PartHead:
+			// The head and hat are laid out as usual
+		case layer == LayerOverlay:
+			// Only the hat existed before 1.8
+			return SkinBox{}, false
+		case part == PartLeftArm:
+			// The left limbs are the right limbs flipped
+			box = mirroredBox(skinLayout[PartRightArm][LayerBase], box)
+		case part == PartLeftLeg:
+			box = mirroredBox(skinLayout[PartRightLeg][LayerBase], box)
+		}
+	}
+
+	if s.IsSlim() && (part == PartRightArm || part == PartLeftArm) {
+		box.W = 3
+	}
+
+	return box.scaled(unit), true
+}
+
+// mirroredBox lays out the box over the texture of another
+func mirroredBox(texture SkinBox, box SkinBox) SkinBox {
+	texture.Name, texture.Part, texture.Mirror = box.Name, box.Part, true
+	return texture
+}
+
+// Boxes returns every box present on the skin
+func (s *Skin) Boxes() []SkinBox {
+	var boxes []SkinBox
+	for _, layer := range []SkinLayer{LayerBase, LayerOverlay} {
+		for _, part := range SkinParts {
+			if box, ok := s.Box(part, layer); ok {
+				boxes = append(boxes, box)
+			}
+		}
+	}
+	return boxes
+}
@@ -0,0 +1,169 @@
+package minecraft
+
+import (
+	"image"
+	"image/color"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// MatteReport describes what SanitizeOverlay found and cleaned
+type MatteReport struct {
+	// Matte is the background colour from the AlphaSig, if it was opaque
+	Matte *color.NRGBA
+	// Regions are the overlay faces that were made transparent, eg. "hat.front"
+	Regions []string
+}
+
+// Cleaned reports whether anything was stripped from the skin
+func (r MatteReport) Cleaned() bool {
+	return len(r.Regions) > 0
+}
+
+// matte returns the background colour of the texture when the AlphaSig shows
+// it was filled with an opaque one (the top-left corner is never drawn)
+func (t *Texture) matte() *color.NRGBA {
+	if t.AlphaSig[3] != 0xff {
+		return nil
+	}
+	return &color.NRGBA{t.AlphaSig[0], t.AlphaSig[1], t.AlphaSig[2], t.AlphaSig[3]}
+}
+
+// DetectMatte returns the overlay faces that SanitizeOverlay would strip,
+// without changing the skin
+func (s *Skin) DetectMatte() ([]string, error) {
+	if _, err := s.layoutUnit(); err != nil {
+		return nil, errors.Wrap(err, "unable to DetectMatte")
+	}
+
+	img := toNRGBA(s.Image)
+	var regions []string
+
+	if s.IsLegacy() {
+		// Minecraft ignores a legacy hat that has no transparency at all,
+		// which is how old skins ended up with solid black hats
+		hat, _ := s.Box(PartHead, LayerOverlay)
+		if !hasTransparency(img, hat.Area()) {
+			for _, face := range BoxFaces {
+				regions = append(regions, hat.FaceName(face))
+			}
+		}
+		return regions, nil
+	}
+
+	// Modern skins are drawn as they are, so only strip faces which are
+	// entirely the background matte
+	matte := s.matte()
+	if matte == nil {
+		return nil, nil
+	}
+	for _, box := range s.Boxes() {
+		if box.Layer != LayerOverlay {
+			continue
+		}
+		for _, face := range BoxFaces {
+			if filledWith(img, box.Face(face), *matte) {
+				regions = append(regions, box.FaceName(face))
+			}
+		}
+	}
+	return regions, nil
+}
+
+// SanitizeOverlay returns a copy of the skin with any overlay faces filled
+// with a matte made transparent, along with a report of what was cleaned.
+// When there was nothing to clean the skin is returned as it is, raw bytes and
+// all.
+func (s *Skin) SanitizeOverlay() (Skin, MatteReport, error) {
+	regions, err := s.DetectMatte()
+	if err != nil {
+		return Skin{}, MatteReport{}, errors.Wrap(err, "unable to SanitizeOverlay")
+	}
+
+	report := MatteReport{Matte: s.matte(), Regions: regions}
+	if !report.Cleaned() {
+		return *s, report, nil
+	}
+
+	clean := s.clone()
+
+	img := clean.Image.(*image.NRGBA)
+	if s.IsLegacy() {
+		hat, _ := s.Box(PartHead, LayerOverlay)
+		clearRect(img, hat.Area())
+	} else {
+		for _, box := range s.Boxes() {
+			for _, face := range BoxFaces {
+				if containsString(regions, box.FaceName(face)) {
+					clearRect(img, box.Face(face))
+				}
+			}
+		}
+	}
+
+	clean.sign()
+	return clean, report, nil
+}
+
+// clone returns a copy of the skin with its own NRGBA image, for editing
+func (s *Skin) clone() Skin {
+	clone := *s
+	src := toNRGBA(s.Image)
+	img := image.NewNRGBA(src.Rect)
+	copy(img.Pix, src.Pix)
+	clone.Image = img
+	// The clone is about to be changed, so it is no longer the texture that
+	// was served: drop the served bytes, the response describing them and
+	// where it came from, so nothing mistakes it for the original
+	clone.Raw, clone.ContentLength = nil, -1
+	clone.Header, clone.FinalURL = nil, ""
+	clone.URL, clone.Timestamp = "", time.Time{}
+	clone.Source = "Edited"
+	return clone
+}
+
+// hasTransparency reports whether any pixel in the area is (mostly) see-through
+func hasTransparency(img *image.NRGBA, rect image.Rectangle) bool {
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			if img.NRGBAAt(x, y).A < 0x80 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// filledWith reports whether every pixel in the area is the given colour
+func filledWith(img *image.NRGBA, rect image.Rectangle, c color.NRGBA) bool {
+	if rect.Empty() {
+		return false
+	}
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			if img.NRGBAAt(x, y) != c {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// clearRect makes the area fully transparent
+func clearRect(img *image.NRGBA, rect image.Rectangle) {
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{})
+		}
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,204 @@
+package minecraft
+
+import (
+	"encoding/hex"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// skinHashGrid is how many cells across and down each face is averaged over
+const skinHashGrid = 2
+
+// skinHashLen is the number of bytes in a SkinHash: an RGBA colour for each
+// cell of each face of each part
+var skinHashLen = len(SkinParts) * len(BoxFaces) * skinHashGrid * skinHashGrid * 4
+
+// SkinHash is a perceptual hash of a skin, made up of the average colour of
+// each face (as it looks with the overlay drawn over it). Unlike Hash, a small
+// edit to a skin only moves its SkinHash a little, and the same look in a
+// legacy, slim or HD layout hashes alike.
+type SkinHash []byte
+
+// String returns the hash as hex
+func (h SkinHash) String() string {
+	return hex.EncodeToString(h)
+}
+
+// ParseSkinHash reads a hash previously returned by SkinHash.String
+func ParseSkinHash(s string) (SkinHash, error) {
+	h, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to ParseSkinHash")
+	}
+	if len(h) != skinHashLen {
+		return nil, errors.Errorf("unable to ParseSkinHash: expected %d bytes, got %d", skinHashLen, len(h))
+	}
+	return SkinHash(h), nil
+}
+
+// Distance returns how different two hashes are, from 0 (the same) to 1
+func (h SkinHash) Distance(other SkinHash) float64 {
+	if len(h) != skinHashLen || len(other) != skinHashLen {
+		return 1
+	}
+
+	total := 0.0
+	for i := 0; i < len(h); i += 4 {
+		sum := 0.0
+		for c := 0; c < 4; c++ {
+			d := float64(h[i+c]) - float64(other[i+c])
+			sum += d * d
+		}
+		total += math.Sqrt(sum) / (0xff * 2)
+	}
+	return total / float64(len(h)/4)
+}
+
+// PerceptualHash returns the SkinHash of the skin
+func (s *Skin) PerceptualHash() (SkinHash, error) {
+	if _, err := s.layoutUnit(); err != nil {
+		return nil, errors.Wrap(err, "unable to PerceptualHash")
+	}
+
+	hash := make(SkinHash, 0, skinHashLen)
+	for _, part := range SkinParts {
+		base, _ := s.Box(part, LayerBase)
+		overlay, hasOverlay := s.Box(part, LayerOverlay)
+
+		for _, f := range BoxFaces {
+			face := faceImage(s.Image, base, f)
+			if hasOverlay {
+				draw.Draw(face, face.Rect, faceImage(s.Image, overlay, f), image.Point{}, draw.Over)
+			}
+			for _, c := range cellAverages(face, skinHashGrid) {
+				hash = append(hash, c.R, c.G, c.B, c.A)
+			}
+		}
+	}
+	return hash, nil
+}
+
+// cellAverages splits the image into a grid x grid of cells and returns the
+// average colour of each, weighting each pixel by how opaque it is
+func cellAverages(img *image.NRGBA, grid int) []color.NRGBA {
+	width, height := img.Rect.Dx(), img.Rect.Dy()
+	averages := make([]color.NRGBA, 0, grid*grid)
+
+	for gy := 0; gy < grid; gy++ {
+		for gx := 0; gx < grid; gx++ {
+			var r, g, b, a float64
+			count := 0
+			for y := gy * height / grid; y < (gy+1)*height/grid; y++ {
+				for x := gx * width / grid; x < (gx+1)*width/grid; x++ {
+					c := img.NRGBAAt(img.Rect.Min.X+x, img.Rect.Min.Y+y)
+					alpha := float64(c.A) / 0xff
+					r += float64(c.R) * alpha
+					g += float64(c.G) * alpha
+					b += float64(c.B) * alpha
+					a += alpha
+					count++
+				}
+			}
+
+			if a == 0 {
+				averages = append(averages, color.NRGBA{})
+				continue
+			}
+			averages = append(averages, color.NRGBA{
+				R: uint8(r/a + 0.5),
+				G: uint8(g/a + 0.5),
+				B: uint8(b/a + 0.5),
+				A: uint8(a/float64(count)*0xff + 0.5),
+			})
+		}
+	}
+	return averages
+}
+
+// SkinMatch is a result from SkinIndex.Nearest
+type SkinMatch struct {
+	ID       string
+	Distance float64
+}
+
+// SkinIndex is an in-memory collection of SkinHashes which can be searched for
+// skins that look like another, eg. to find reuploads of a stolen skin
+type SkinIndex struct {
+	mu     sync.RWMutex
+	hashes map[string]SkinHash
+}
+
+// NewSkinIndex returns an empty SkinIndex
+func NewSkinIndex() *SkinIndex {
+	return &SkinIndex{hashes: make(map[string]SkinHash)}
+}
+
+// Add hashes the skin and stores it under the ID, replacing any previous entry
+func (idx *SkinIndex) Add(id string, skin *Skin) error {
+	hash, err := skin.PerceptualHash()
+	if err != nil {
+		return errors.Wrap(err, "unable to Add to SkinIndex")
+	}
+	idx.AddHash(id, hash)
+	return nil
+}
+
+// AddHash stores an existing hash under the ID, replacing any previous entry
+func (idx *SkinIndex) AddHash(id string, hash SkinHash) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.hashes[id] = hash
+}
+
+// Remove deletes the ID from the index
+func (idx *SkinIndex) Remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	delete(idx.hashes, id)
+}
+
+// Len returns the number of skins in the index
+func (idx *SkinIndex) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.hashes)
+}
+
+// Nearest returns up to n skins from the index that look most like the skin,
+// closest first
+func (idx *SkinIndex) Nearest(skin *Skin, n int) ([]SkinMatch, error) {
+	hash, err := skin.PerceptualHash()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to search SkinIndex")
+	}
+	return idx.NearestHash(hash, n), nil
+}
+
+// NearestHash returns up to n skins from the index closest to the hash,
+// closest first
+func (idx *SkinIndex) NearestHash(hash SkinHash, n int) []SkinMatch {
+	idx.mu.RLock()
+	matches := make([]SkinMatch, 0, len(idx.hashes))
+	for id, other := range idx.hashes {
+		matches = append(matches, SkinMatch{ID: id, Distance: hash.Distance(other)})
+	}
+	idx.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Distance == matches[j].Distance {
+			return matches[i].ID < matches[j].ID
+		}
+		return matches[i].Distance < matches[j].Distance
+	})
+
+	if n >= 0 && len(matches) > n {
+		matches = matches[:n]
+	}
+	return matches
+}
@@ -3,7 +3,11 @@ package minecraft
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
 	"image"
+	"strings"
+	"sync"
 	// If we work with PNGs we need this
 	_ "image/png"
 
@@ -56,6 +60,76 @@ func FetchSkinForSteve() (Skin, error) {
 	return *skin, skin.FetchSteve()
 }
 
+// AlexURL is Mojang's texture of Alex, the slim armed default skin
+const AlexURL = "http://textures.minecraft.net/texture/3b60a1f6d562f52aaebbf1434f1de147933a3affe0e764fa49ea057536623cd3"
+
+// alex keeps Alex once fetched, as the texture never changes
+var alex struct {
+	sync.Mutex
+	skin *Skin
+}
+
+// FetchSkinForAlex returns Alex. Unlike Steve the texture isn't bundled, so
+// it is fetched from AlexURL the first time and kept after that.
+func (mc *Minecraft) FetchSkinForAlex() (Skin, error) {
+	alex.Lock()
+	defer alex.Unlock()
+
+	if alex.skin == nil {
+		skin := &Skin{Texture{Mc: mc, URL: AlexURL, Model: SkinModelSlim}}
+		if err := skin.Fetch(); err != nil {
+			return Skin{Texture{Mc: mc}}, errors.Wrap(err, "unable to FetchSkinForAlex")
+		}
+		skin.Source = "Alex"
+		alex.skin = skin
+	}
+
+	skin := *alex.skin
+	skin.Mc = mc
+	return skin, nil
+}
+
+// DefaultSkinModel returns the model of the default skin the game gave the
+// player with the UUID before 1.19.3: SkinModelSlim (Alex) when Java's
+// UUID.hashCode() is odd, otherwise SkinModelClassic (Steve). Newer versions
+// pick from nine default skins instead, which this legacy two skin rule
+// doesn't cover.
+func DefaultSkinModel(uuid string) (string, error) {
+	if !RegexUUID.MatchString(uuid) {
+		return "", errors.Errorf("unable to get DefaultSkinModel: %q is not a UUID", uuid)
+	}
+	b, _ := hex.DecodeString(strings.Replace(uuid, "-", "", -1))
+
+	// hashCode XORs the four 32 bit words together, so only their lowest
+	// bits decide if it is odd
+	var odd uint32
+	for i := 0; i < 16; i += 4 {
+		odd ^= binary.BigEndian.Uint32(b[i:]) & 1
+	}
+	if odd == 1 {
+		return SkinModelSlim, nil
+	}
+	return SkinModelClassic, nil
+}
+
+// FetchDefaultSkin returns Steve or Alex for the player with the UUID, as
+// chosen by DefaultSkinModel
+func (mc *Minecraft) FetchDefaultSkin(uuid string) (Skin, error) {
+	model, err := DefaultSkinModel(uuid)
+	if err != nil {
+		return Skin{}, errors.Wrap(err, "unable to FetchDefaultSkin")
+	}
+
+	if model == SkinModelClassic {
+		return FetchSkinForSteve()
+	}
+	skin, err := mc.FetchSkinForAlex()
+	if err != nil {
+		return Skin{}, errors.Wrap(err, "unable to FetchDefaultSkin")
+	}
+	return skin, nil
+}
+
 // The constant below contains Mojang AB copyrighted content.
 // The use of this imagery is subject to their copyright.
 
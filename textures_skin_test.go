@@ -0,0 +1,687 @@
+// textures_skin_test.go
+package minecraft
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+// signedSkin fills in the Hash and AlphaSig of a test skin as Decode would
+func signedSkin(skin Skin) Skin {
+	skin.sign()
+	return skin
+}
+
+func TestSkinMatte(t *testing.T) {
+	black := color.NRGBA{0, 0, 0, 255}
+
+	Convey("Test Skin.SanitizeOverlay", t, func() {
+
+		Convey("Steve has nothing to clean", func() {
+			steve, _ := FetchSkinForSteve()
+			clean, report, err := steve.SanitizeOverlay()
+
+			So(err, ShouldBeNil)
+			So(report.Cleaned(), ShouldBeFalse)
+			So(clean.Hash, ShouldEqual, SteveHash)
+		})
+
+		Convey("Skins with nothing to clean keep their raw bytes", func() {
+			skin, _ := FetchSkinForSteve()
+			skin.Raw, skin.Header = []byte("\x89PNG"), http.Header{"Etag": {`"abc"`}}
+			clean, report, err := skin.SanitizeOverlay()
+
+			So(err, ShouldBeNil)
+			So(report.Cleaned(), ShouldBeFalse)
+			So(clean.Raw, ShouldResemble, skin.Raw)
+			So(clean.Header, ShouldResemble, skin.Header)
+		})
+
+		Convey("Legacy skins with a solid hat lose it", func() {
+			skin := testSkin(64, 32, SkinModelClassic)
+			fillRect(skin.Image.(*image.NRGBA), image.Rect(32, 0, 64, 16), black)
+			skin = signedSkin(skin)
+			skin.Raw, skin.Header, skin.FinalURL = []byte("\x89PNG"), http.Header{"Etag": {`"abc"`}}, "http://textures.minecraft.net/texture/abc"
+
+			regions, err := skin.DetectMatte()
+			So(err, ShouldBeNil)
+			So(regions, ShouldResemble, []string{"hat.top", "hat.bottom", "hat.right", "hat.front", "hat.left", "hat.back"})
+
+			clean, report, err := skin.SanitizeOverlay()
+			So(err, ShouldBeNil)
+			So(report.Regions, ShouldResemble, regions)
+			So(clean.Image.(*image.NRGBA).NRGBAAt(40, 8).A, ShouldEqual, 0)
+			So(clean.Hash, ShouldNotEqual, skin.Hash)
+			So(clean.Raw, ShouldBeNil)
+			So(clean.Header, ShouldBeNil)
+			So(clean.FinalURL, ShouldBeEmpty)
+
+			// The original is left alone
+			So(skin.Image.(*image.NRGBA).NRGBAAt(40, 8), ShouldResemble, black)
+		})
+
+		Convey("Legacy skins with some transparency in the hat keep it", func() {
+			skin := testSkin(64, 32, SkinModelClassic)
+			skin.Image.(*image.NRGBA).SetNRGBA(63, 15, color.NRGBA{})
+
+			regions, err := skin.DetectMatte()
+			So(err, ShouldBeNil)
+			So(regions, ShouldBeEmpty)
+		})
+
+		Convey("Modern skins only lose faces filled with the matte", func() {
+			skin := testSkin(64, 64, SkinModelClassic)
+			img := skin.Image.(*image.NRGBA)
+			fillRect(img, image.Rect(0, 0, 8, 8), black)
+			hat, _ := skin.Box(PartHead, LayerOverlay)
+			fillRect(img, hat.Face(FaceFront), black)
+			skin = signedSkin(skin)
+
+			clean, report, err := skin.SanitizeOverlay()
+			So(err, ShouldBeNil)
+			So(*report.Matte, ShouldResemble, black)
+			So(report.Regions, ShouldResemble, []string{"hat.front"})
+			So(clean.Image.(*image.NRGBA).NRGBAAt(40, 8).A, ShouldEqual, 0)
+			So(clean.Image.(*image.NRGBA).NRGBAAt(40, 0), ShouldResemble, boxColour(6, FaceTop))
+		})
+
+		Convey("Modern skins without a matte are left alone", func() {
+			skin := signedSkin(testSkin(64, 64, SkinModelClassic))
+
+			_, report, err := skin.SanitizeOverlay()
+			So(err, ShouldBeNil)
+			So(report.Matte, ShouldBeNil)
+			So(report.Cleaned(), ShouldBeFalse)
+		})
+
+		Convey("Skins without an image should gracefully fail", func() {
+			_, _, err := (&Skin{}).SanitizeOverlay()
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to SanitizeOverlay: unable to DetectMatte: skin has no image")
+		})
+
+	})
+
+}
+
+func TestSkinPerceptualHash(t *testing.T) {
+	steve, _ := FetchSkinForSteve()
+	clone1018, _ := mcTest.FetchSkinUsername("clone1018")
+	citricsquid, _ := mcTest.FetchSkinUsername("citricsquid")
+
+	Convey("Test Skin.PerceptualHash", t, func() {
+
+		Convey("The same skin should have no distance", func() {
+			a, err := steve.PerceptualHash()
+			So(err, ShouldBeNil)
+			b, _ := steve.PerceptualHash()
+
+			So(a.Distance(b), ShouldEqual, 0)
+		})
+
+		Convey("A one pixel edit should barely move the hash", func() {
+			tweaked := steve.clone()
+			tweaked.Image.(*image.NRGBA).SetNRGBA(10, 10, color.NRGBA{255, 0, 0, 255})
+			tweaked.sign()
+
+			a, _ := steve.PerceptualHash()
+			b, _ := tweaked.PerceptualHash()
+			So(tweaked.Hash, ShouldNotEqual, steve.Hash)
+			So(a.Distance(b), ShouldBeGreaterThan, 0)
+			So(a.Distance(b), ShouldBeLessThan, 0.01)
+		})
+
+		Convey("Different skins should be further apart", func() {
+			a, _ := steve.PerceptualHash()
+			b, _ := clone1018.PerceptualHash()
+
+			So(a.Distance(b), ShouldBeGreaterThan, 0.05)
+		})
+
+		Convey("Hashes should survive being written out", func() {
+			a, _ := steve.PerceptualHash()
+			b, err := ParseSkinHash(a.String())
+
+			So(err, ShouldBeNil)
+			So(b, ShouldResemble, a)
+
+			_, err = ParseSkinHash("abcd")
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to ParseSkinHash: expected 576 bytes, got 2")
+		})
+
+	})
+
+	Convey("Test SkinIndex", t, func() {
+		idx := NewSkinIndex()
+		So(idx.Add("steve", &steve), ShouldBeNil)
+		So(idx.Add("clone1018", &clone1018), ShouldBeNil)
+		So(idx.Add("citricsquid", &citricsquid), ShouldBeNil)
+
+		Convey("A reupload should find the original first", func() {
+			reupload := steve.clone()
+			reupload.Image.(*image.NRGBA).SetNRGBA(20, 20, color.NRGBA{0, 0, 0, 255})
+
+			matches, err := idx.Nearest(&reupload, 2)
+			So(err, ShouldBeNil)
+			So(matches, ShouldHaveLength, 2)
+			So(matches[0].ID, ShouldEqual, "steve")
+			So(matches[0].Distance, ShouldBeLessThan, matches[1].Distance)
+		})
+
+		Convey("Removed skins should not be found", func() {
+			idx.Remove("steve")
+
+			So(idx.Len(), ShouldEqual, 2)
+			matches, _ := idx.Nearest(&steve, 5)
+			So(matches, ShouldHaveLength, 2)
+			So(matches[0].ID, ShouldNotEqual, "steve")
+		})
+
+		Convey("Skins without an image should gracefully fail", func() {
+			err := idx.Add("empty", &Skin{})
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to Add to SkinIndex: unable to PerceptualHash: skin has no image")
+		})
+
+	})
+
+}
+
+func TestSkinValidate(t *testing.T) {
+
+	Convey("Test Skin.Validate", t, func() {
+
+		Convey("A well formed skin should have no violations", func() {
+			skin := testSkin(64, 64, SkinModelSlim)
+			report := skin.Validate()
+
+			So(report.Valid(), ShouldBeTrue)
+			So(report.Violations, ShouldBeEmpty)
+		})
+
+		Convey("Steve should be valid", func() {
+			steve, _ := FetchSkinForSteve()
+			report := steve.Validate()
+
+			So(report.Valid(), ShouldBeTrue)
+		})
+
+		Convey("Transparency in the base layer should be reported by region", func() {
+			skin := testSkin(64, 64, SkinModelClassic)
+			img := skin.Image.(*image.NRGBA)
+			img.SetNRGBA(9, 9, color.NRGBA{})
+			img.SetNRGBA(20, 20, color.NRGBA{0, 0, 0, 128})
+			// Transparency in the overlay is fine
+			img.SetNRGBA(40, 8, color.NRGBA{})
+
+			report := skin.Validate()
+			So(report.Valid(), ShouldBeFalse)
+			So(report.Errors(), ShouldResemble, []Violation{{
+				Check:    "base-transparency",
+				Severity: SeverityError,
+				Regions:  []string{"head.front", "body.front"},
+				Message:  "the base layer must be opaque but has transparent pixels",
+			}})
+		})
+
+		Convey("Data in unused areas should be a warning", func() {
+			skin := testSkin(64, 64, SkinModelClassic)
+			img := skin.Image.(*image.NRGBA)
+			img.SetNRGBA(0, 0, color.NRGBA{0, 0, 0, 255})
+			img.SetNRGBA(60, 20, color.NRGBA{0, 0, 0, 255})
+
+			report := skin.Validate()
+			So(report.Valid(), ShouldBeTrue)
+			So(report.Warnings(), ShouldHaveLength, 1)
+			So(report.Warnings()[0].Check, ShouldEqual, "unused-data")
+			So(report.Warnings()[0].Regions, ShouldResemble, []string{"head.unused", "unused"})
+		})
+
+		Convey("Slim skins have more unused space than classic ones", func() {
+			skin := testSkin(64, 64, SkinModelClassic)
+			skin.Model = SkinModelSlim
+
+			report := skin.Validate()
+			So(report.Warnings(), ShouldHaveLength, 1)
+			So(report.Warnings()[0].Regions, ShouldResemble, []string{"left_arm.unused", "left_sleeve.unused", "right_arm.unused", "right_sleeve.unused", "unused"})
+		})
+
+		Convey("Bad images should be errors", func() {
+			So((&Skin{}).Validate().Errors()[0].Check, ShouldEqual, "image")
+
+			odd := Skin{Texture{Image: image.NewNRGBA(image.Rect(0, 0, 64, 48))}}
+			So(odd.Validate().Errors()[0].Message, ShouldEqual, "unsupported skin dimensions 64x48, expected 64x64 or 64x32")
+
+			gray := Skin{Texture{Image: image.NewGray(image.Rect(0, 0, 64, 64))}}
+			So(gray.Validate().Errors()[0].Check, ShouldEqual, "color-model")
+
+			hd := testSkin(128, 128, SkinModelClassic)
+			So(hd.Validate().Valid(), ShouldBeTrue)
+			So(hd.Validate().Warnings()[0].Check, ShouldEqual, "dimensions")
+		})
+
+	})
+
+	Convey("Test Skin.Validate with decoded skins", t, func() {
+		steve, _ := FetchSkinForSteve()
+
+		decode := func(img image.Image) Skin {
+			buf := &bytes.Buffer{}
+			png.Encode(buf, img)
+			skin := Skin{}
+			So(skin.Decode(buf), ShouldBeNil)
+			return skin
+		}
+
+		Convey("Skins without alpha should be errors once decoded", func() {
+			gray := image.NewGray(steve.Image.Bounds())
+			opaque := image.NewRGBA(steve.Image.Bounds())
+			draw.Draw(opaque, opaque.Rect, steve.Image, image.Point{}, draw.Src)
+			for i := 3; i < len(opaque.Pix); i += 4 {
+				opaque.Pix[i] = 0xff
+			}
+
+			for _, img := range []image.Image{gray, opaque} {
+				skin := decode(img)
+				_, nrgba := skin.Image.(*image.NRGBA)
+				So(nrgba, ShouldBeTrue)
+				So(skin.Validate().Errors()[0].Check, ShouldEqual, "color-model")
+			}
+		})
+
+		Convey("Paletted skins should be allowed", func() {
+			pal := color.Palette{color.NRGBA{}, color.NRGBA{0, 0, 0, 255}}
+			paletted := image.NewPaletted(steve.Image.Bounds(), pal)
+			for y := 0; y < paletted.Rect.Dy(); y++ {
+				for x := 0; x < paletted.Rect.Dx(); x++ {
+					paletted.SetColorIndex(x, y, steve.Image.(*image.NRGBA).NRGBAAt(x, y).A/0xff)
+				}
+			}
+
+			skin := decode(paletted)
+			So(skin.Validate().Valid(), ShouldBeTrue)
+		})
+
+	})
+
+	Convey("Test Cape.Validate", t, func() {
+
+		Convey("citricsquid cape should be valid", func() {
+			cape, _ := mcTest.FetchCapeUsername("citricsquid")
+			report := cape.Validate()
+
+			So(report.Valid(), ShouldBeTrue)
+		})
+
+		Convey("Legacy capes should be valid", func() {
+			cape := Cape{Texture{Image: image.NewNRGBA(image.Rect(0, 0, 22, 17))}}
+
+			So(cape.Validate().Violations, ShouldBeEmpty)
+		})
+
+		Convey("Odd sized capes should be errors", func() {
+			cape := Cape{Texture{Image: image.NewNRGBA(image.Rect(0, 0, 64, 64))}}
+			report := cape.Validate()
+
+			So(report.Valid(), ShouldBeFalse)
+			So(report.Errors()[0].Message, ShouldEqual, "unsupported cape dimensions 64x64, expected 64x32 or 22x17")
+		})
+
+		Convey("Data in unused areas should be a warning", func() {
+			img := image.NewNRGBA(image.Rect(0, 0, 64, 32))
+			img.SetNRGBA(0, 0, color.NRGBA{0, 0, 0, 255})
+			img.SetNRGBA(60, 30, color.NRGBA{0, 0, 0, 255})
+			cape := Cape{Texture{Image: img}}
+
+			report := cape.Validate()
+			So(report.Warnings()[0].Regions, ShouldResemble, []string{"cape.unused", "unused"})
+		})
+
+	})
+
+}
+
+func TestSkinEdit(t *testing.T) {
+	red := color.NRGBA{255, 0, 0, 255}
+	blue := color.NRGBA{0, 0, 255, 255}
+
+	Convey("Test Skin.Paste", t, func() {
+
+		Convey("Images should be resized to fill the region", func() {
+			skin := signedSkin(testSkin(64, 64, SkinModelClassic))
+			img := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+			img.SetNRGBA(0, 0, red)
+
+			edited, err := skin.Paste("hat.front", img)
+			So(err, ShouldBeNil)
+			So(edited.Image.At(40, 8), ShouldResemble, red)
+			So(edited.Image.At(47, 15), ShouldResemble, red)
+			So(edited.Image.At(48, 8), ShouldResemble, boxColour(6, FaceLeft))
+			So(edited.Hash, ShouldNotEqual, skin.Hash)
+
+			// The original is left alone
+			So(skin.Image.At(40, 8), ShouldResemble, boxColour(6, FaceFront))
+		})
+
+		Convey("Edited skins should no longer claim to be the texture they came from", func() {
+			skin := signedSkin(testSkin(64, 64, SkinModelClassic))
+			skin.URL, skin.Source, skin.Timestamp = "http://textures.minecraft.net/texture/abc", "SessionProfile", time.Unix(1500000000, 0)
+			skin.ContentLength = 1234
+
+			edited, err := skin.Paste("hat.front", image.NewNRGBA(image.Rect(0, 0, 1, 1)))
+			So(err, ShouldBeNil)
+			So(edited.URL, ShouldBeEmpty)
+			So(edited.Source, ShouldEqual, "Edited")
+			So(edited.Timestamp.IsZero(), ShouldBeTrue)
+			So(edited.ContentLength, ShouldEqual, -1)
+		})
+
+		Convey("Mirrored faces should be flipped", func() {
+			skin := testSkin(64, 32, SkinModelClassic)
+			img := image.NewNRGBA(image.Rect(0, 0, 4, 12))
+			fillRect(img, image.Rect(0, 0, 2, 12), red)
+			fillRect(img, image.Rect(2, 0, 4, 12), blue)
+
+			edited, err := skin.Paste("left_arm.front", img)
+			So(err, ShouldBeNil)
+			So(edited.Image.At(44, 20), ShouldResemble, blue)
+			So(edited.Image.At(47, 20), ShouldResemble, red)
+
+			_, err = skin.Paste("left_arm", img)
+			So(err.Error(), ShouldEqual, "unable to Paste: left_arm is mirrored, paste its faces instead")
+		})
+
+		Convey("Unknown regions should error", func() {
+			skin := testSkin(64, 32, SkinModelClassic)
+
+			_, err := skin.Paste("jacket", image.NewNRGBA(image.Rect(0, 0, 1, 1)))
+			So(err.Error(), ShouldEqual, `unable to Paste: unknown region "jacket"`)
+
+			_, err = skin.Paste("head.side", image.NewNRGBA(image.Rect(0, 0, 1, 1)))
+			So(err.Error(), ShouldEqual, `unable to Paste: unknown region "head.side"`)
+		})
+
+	})
+
+	Convey("Test Skin.Recolor and Skin.HueShift", t, func() {
+
+		Convey("Only the regions given should be recoloured", func() {
+			skin := testSkin(64, 64, SkinModelClassic)
+			edited, err := skin.Recolor(func(c color.NRGBA) color.NRGBA {
+				c.R++
+				return c
+			}, "head", "head.front")
+
+			So(err, ShouldBeNil)
+			So(edited.Image.At(8, 8), ShouldResemble, color.NRGBA{1, 120, 100, 255})
+			So(edited.Image.At(8, 0), ShouldResemble, color.NRGBA{1, 0, 100, 255})
+			So(edited.Image.At(40, 8), ShouldResemble, boxColour(6, FaceFront))
+			// Blank areas stay blank
+			So(edited.Image.At(0, 0), ShouldResemble, color.NRGBA{})
+		})
+
+		Convey("Red should be shifted to green and blue", func() {
+			img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+			fillRect(img, img.Rect, red)
+			skin := Skin{Texture{Image: img}}
+
+			green, err := skin.HueShift(120)
+			So(err, ShouldBeNil)
+			So(green.Image.At(10, 10), ShouldResemble, color.NRGBA{0, 255, 0, 255})
+
+			shifted, err := skin.HueShift(-120)
+			So(err, ShouldBeNil)
+			So(shifted.Image.At(10, 10), ShouldResemble, blue)
+		})
+
+		Convey("Shifting by a full turn should change nothing", func() {
+			skin := signedSkin(testSkin(64, 64, SkinModelClassic))
+			edited, err := skin.HueShift(360)
+
+			So(err, ShouldBeNil)
+			So(edited.Hash, ShouldEqual, skin.Hash)
+		})
+
+	})
+
+	Convey("Test Skin.FlattenOverlay", t, func() {
+
+		Convey("Overlays should be drawn onto the base layer and cleared", func() {
+			skin := testSkin(64, 64, SkinModelClassic)
+			skin.Image.(*image.NRGBA).SetNRGBA(40, 8, color.NRGBA{})
+
+			flat, err := skin.FlattenOverlay()
+			So(err, ShouldBeNil)
+			So(flat.Image.At(8, 8), ShouldResemble, boxColour(0, FaceFront))
+			So(flat.Image.At(9, 8), ShouldResemble, boxColour(6, FaceFront))
+			So(flat.Image.At(20, 20), ShouldResemble, boxColour(7, FaceFront))
+			So(flat.Image.At(41, 8), ShouldResemble, color.NRGBA{})
+			So(flat.Image.At(20, 36), ShouldResemble, color.NRGBA{})
+		})
+
+	})
+
+	Convey("Test Skin.MirrorLimbs", t, func() {
+
+		Convey("The left limbs should become flipped copies of the right", func() {
+			skin := testSkin(64, 64, SkinModelSlim)
+			mirrored, err := skin.MirrorLimbs()
+			So(err, ShouldBeNil)
+
+			leftArm, _ := mirrored.Box(PartLeftArm, LayerBase)
+			So(mirrored.Image.At(leftArm.Face(FaceFront).Min.X, leftArm.Face(FaceFront).Min.Y), ShouldResemble, boxColour(2, FaceFront))
+			So(mirrored.Image.At(leftArm.Face(FaceRight).Min.X, leftArm.Face(FaceRight).Min.Y), ShouldResemble, boxColour(2, FaceLeft))
+
+			leftPants, _ := mirrored.Box(PartLeftLeg, LayerOverlay)
+			So(mirrored.Image.At(leftPants.Face(FaceLeft).Min.X, leftPants.Face(FaceLeft).Min.Y), ShouldResemble, boxColour(10, FaceRight))
+		})
+
+		Convey("Only opposite limbs of modern skins can be mirrored", func() {
+			modern := testSkin(64, 64, SkinModelClassic)
+			_, err := modern.MirrorLimb(PartRightArm, PartLeftLeg)
+			So(err.Error(), ShouldEqual, "unable to MirrorLimb: left_leg is not the opposite of right_arm")
+
+			legacy := testSkin(64, 32, SkinModelClassic)
+			_, err = legacy.MirrorLimbs()
+			So(err.Error(), ShouldEqual, "unable to MirrorLimbs: unable to MirrorLimb: legacy skins always mirror their limbs")
+		})
+
+	})
+
+}
+
+func TestSkinConvertModel(t *testing.T) {
+	// Every pixel records where it came from
+	gradientSkin := func(model string) Skin {
+		img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+		for y := 0; y < 64; y++ {
+			for x := 0; x < 64; x++ {
+				img.SetNRGBA(x, y, color.NRGBA{uint8(x), uint8(y), 0, 255})
+			}
+		}
+		return Skin{Texture{Image: img, Model: model}}
+	}
+	from := func(x, y int) color.NRGBA {
+		return color.NRGBA{uint8(x), uint8(y), 0, 255}
+	}
+
+	Convey("Test Skin.ConvertModel", t, func() {
+
+		Convey("Classic to slim should remove the chosen column", func() {
+			skin := gradientSkin(SkinModelClassic)
+
+			slim, err := skin.ConvertModel(SkinModelSlim, ArmOuter)
+			So(err, ShouldBeNil)
+			So(slim.Model, ShouldEqual, SkinModelSlim)
+			// Right arm front, the outside is on the left
+			So(slim.Image.At(44, 20), ShouldResemble, from(45, 20))
+			So(slim.Image.At(46, 20), ShouldResemble, from(47, 20))
+			// Right arm back, the outside is on the right
+			So(slim.Image.At(51, 20), ShouldResemble, from(52, 20))
+			So(slim.Image.At(53, 20), ShouldResemble, from(54, 20))
+			// Left sleeve front, the outside is on the right
+			So(slim.Image.At(52, 52), ShouldResemble, from(52, 52))
+			So(slim.Image.At(54, 52), ShouldResemble, from(54, 52))
+			// The sides are moved along
+			So(slim.Image.At(47, 20), ShouldResemble, from(48, 20))
+			// And the spare columns cleared
+			So(slim.Image.At(55, 20), ShouldResemble, color.NRGBA{})
+
+			slim, _ = skin.ConvertModel(SkinModelSlim, ArmInner)
+			So(slim.Image.At(44, 20), ShouldResemble, from(44, 20))
+			So(slim.Image.At(46, 20), ShouldResemble, from(46, 20))
+
+			slim, _ = skin.ConvertModel(SkinModelSlim, ArmMiddle)
+			So(slim.Image.At(45, 20), ShouldResemble, from(45, 20))
+			So(slim.Image.At(46, 20), ShouldResemble, from(47, 20))
+		})
+
+		Convey("Slim to classic should duplicate the chosen column", func() {
+			skin := gradientSkin(SkinModelSlim)
+
+			classic, err := skin.ConvertModel(SkinModelClassic, ArmOuter)
+			So(err, ShouldBeNil)
+			So(classic.Model, ShouldEqual, SkinModelClassic)
+			So(classic.Image.At(44, 20), ShouldResemble, from(44, 20))
+			So(classic.Image.At(45, 20), ShouldResemble, from(44, 20))
+			So(classic.Image.At(47, 20), ShouldResemble, from(46, 20))
+			So(classic.Image.At(48, 20), ShouldResemble, from(47, 20))
+		})
+
+		Convey("Converting back and forth should keep the unchanged columns", func() {
+			skin := testSkin(64, 64, SkinModelClassic)
+			slim, _ := skin.ConvertModel(SkinModelSlim, ArmInner)
+			classic, _ := slim.ConvertModel(SkinModelClassic, ArmInner)
+
+			before, _ := skin.RenderBody(1, true)
+			after, _ := classic.RenderBody(1, true)
+			So(after.Pix, ShouldResemble, before.Pix)
+		})
+
+		Convey("Converting to the same model should change nothing", func() {
+			skin := signedSkin(gradientSkin(SkinModelSlim))
+			skin.Raw, skin.URL = []byte("\x89PNG"), "http://textures.minecraft.net/texture/abc"
+			slim, err := skin.ConvertModel(SkinModelSlim, ArmOuter)
+
+			So(err, ShouldBeNil)
+			So(slim, ShouldResemble, skin)
+		})
+
+		Convey("Bad conversions should error", func() {
+			skin := gradientSkin(SkinModelClassic)
+			_, err := skin.ConvertModel("wide", ArmOuter)
+			So(err.Error(), ShouldEqual, `unable to ConvertModel: unknown model "wide"`)
+
+			legacy := testSkin(64, 32, SkinModelClassic)
+			_, err = legacy.ConvertModel(SkinModelSlim, ArmOuter)
+			So(err.Error(), ShouldEqual, "unable to ConvertModel: legacy skins only have classic arms")
+		})
+
+	})
+
+}
+
+func TestSkinPalette(t *testing.T) {
+	red := color.NRGBA{255, 0, 0, 255}
+	blue := color.NRGBA{0, 0, 255, 255}
+	black := color.NRGBA{0, 0, 0, 255}
+
+	// A blue player with a red head
+	twoToneSkin := func() Skin {
+		skin := Skin{Texture{Image: image.NewNRGBA(image.Rect(0, 0, 64, 64))}}
+		for _, part := range SkinParts {
+			box, _ := skin.Box(part, LayerBase)
+			for _, face := range BoxFaces {
+				if part == PartHead {
+					fillRect(skin.Image.(*image.NRGBA), box.Face(face), red)
+				} else {
+					fillRect(skin.Image.(*image.NRGBA), box.Face(face), blue)
+				}
+			}
+		}
+		return skin
+	}
+
+	Convey("Test Skin.Palette", t, func() {
+
+		Convey("The dominant colours should be weighted by their pixels", func() {
+			skin := twoToneSkin()
+			palette, err := skin.Palette(5)
+
+			So(err, ShouldBeNil)
+			So(palette, ShouldResemble, []PaletteColor{
+				{Color: blue, Weight: 1248.0 / 1632},
+				{Color: red, Weight: 384.0 / 1632},
+			})
+		})
+
+		Convey("A single colour should be the average", func() {
+			skin := twoToneSkin()
+			palette, err := skin.Palette(1)
+
+			So(err, ShouldBeNil)
+			So(palette, ShouldHaveLength, 1)
+			So(palette[0].Color, ShouldResemble, color.NRGBA{60, 0, 195, 255})
+			So(palette[0].Weight, ShouldEqual, 1)
+		})
+
+		Convey("Palettes can be limited to some parts", func() {
+			skin := twoToneSkin()
+			palette, err := skin.Palette(3, PartHead)
+
+			So(err, ShouldBeNil)
+			So(palette, ShouldResemble, []PaletteColor{{Color: red, Weight: 1}})
+		})
+
+		Convey("The matte should be ignored in the overlays", func() {
+			skin := twoToneSkin()
+			img := skin.Image.(*image.NRGBA)
+			img.SetNRGBA(0, 0, black)
+			jacket, _ := skin.Box(PartBody, LayerOverlay)
+			fillRect(img, jacket.Face(FaceFront), black)
+			img.SetNRGBA(40, 8, black)
+			skin = signedSkin(skin)
+
+			palette, err := skin.Palette(5)
+			So(err, ShouldBeNil)
+			So(palette, ShouldHaveLength, 2)
+		})
+
+		Convey("Steve should have a palette", func() {
+			steve, _ := FetchSkinForSteve()
+			palette, err := steve.Palette(8)
+
+			So(err, ShouldBeNil)
+			So(palette, ShouldHaveLength, 8)
+			So(palette[0].Weight, ShouldBeGreaterThanOrEqualTo, palette[7].Weight)
+
+			var total float64
+			for _, c := range palette {
+				total += c.Weight
+			}
+			So(total, ShouldAlmostEqual, 1)
+		})
+
+		Convey("Bad requests should gracefully fail", func() {
+			skin := twoToneSkin()
+			_, err := skin.Palette(0)
+			So(err.Error(), ShouldEqual, "unable to Palette: colours must be at least 1 (got 0)")
+
+			_, err = (&Skin{}).Palette(1)
+			So(err.Error(), ShouldEqual, "unable to Palette: unable to SanitizeOverlay: unable to DetectMatte: skin has no image")
+		})
+
+	})
+
+}
@@ -1,11 +1,18 @@
 package minecraft
 
 import (
+	"bytes"
+	"context"
 	"crypto/md5"
+	"crypto/sha256"
 	"fmt"
 	"image"
 	"image/draw"
 	"io"
+	"io/ioutil"
+	"log/slog"
+	"net/http"
+	"time"
 	// If we work with PNGs we need this
 	_ "image/png"
 
@@ -18,12 +25,32 @@ type Texture struct {
 	Image image.Image
 	// md5 hash of the texture image
 	Hash string
+	// sha256 hash of the texture image
+	HashSHA256 string
 	// Location we grabbed the texture from. Mojang/S3/Char
 	Source string
 	// 4-byte signature of the background matte for the texture
 	AlphaSig [4]uint8
 	// URL of the texture
 	URL string
+	// Model of the skin as advised by the textures property (eg. "slim")
+	Model string
+	// Raw is the texture exactly as it was served (or decoded), for passing it
+	// on unchanged
+	Raw []byte
+	// ContentLength as advised by the response (-1 when unknown)
+	ContentLength int64
+	// Header of the response the texture was fetched with
+	Header http.Header
+	// FetchedAt is when the texture was fetched
+	FetchedAt time.Time
+	// FinalURL is the URL the texture was fetched from after any redirects
+	FinalURL string
+	// Stale is true when the texture (or the session profile naming it, or the
+	// API profile looking up its player) was served from the Cache past its TTL
+	Stale bool
+	// Timestamp of the textures property the URL came from (zero when not from a SessionProfile)
+	Timestamp time.Time
 	// M is a pointer to the Minecraft struct that is then used for requests against the API
 	Mc *Minecraft
 }
@@ -50,47 +77,105 @@ func (t *Texture) CastToNRGBA(r io.Reader) error {
 
 // Decode takes the image bytes and turns it into our Texture struct
 func (t *Texture) Decode(r io.Reader) error {
-	err := t.CastToNRGBA(r)
+	raw, err := ioutil.ReadAll(r)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	// And md5 hash its pixels
-	hasher := md5.New()
-	hasher.Write(t.Image.(*image.NRGBA).Pix)
-	t.Hash = fmt.Sprintf("%x", hasher.Sum(nil))
+	err = t.CastToNRGBA(bytes.NewReader(raw))
+	if err != nil {
+		return errors.WithStack(err)
+	}
 
-	// Create the alpha signature
+	t.Raw = raw
+	t.sign()
+	return nil
+}
+
+// sign (re)computes the hashes and AlphaSig from the NRGBA Image
+func (t *Texture) sign() {
 	img := t.Image.(*image.NRGBA)
+
+	// And md5 and sha256 hash its pixels
+	t.Hash = fmt.Sprintf("%x", md5.Sum(img.Pix))
+	t.HashSHA256 = fmt.Sprintf("%x", sha256.Sum256(img.Pix))
+
+	// Create the alpha signature
 	t.AlphaSig = [...]uint8{
 		img.Pix[0],
 		img.Pix[1],
 		img.Pix[2],
 		img.Pix[3],
 	}
-
-	return nil
 }
 
 // Fetch performs the GET for the texture, doing any required conversion and saving our Image property
 func (t *Texture) Fetch() error {
-	apiBody, err := t.Mc.apiRequest(t.URL)
-	if apiBody != nil {
-		defer apiBody.Close()
+	return t.fetchContext(context.Background())
+}
+
+func (t *Texture) fetchContext(ctx context.Context) error {
+	op := t.Mc.startOperation(ctx, "minecraft.texture", slog.String("url", t.URL))
+	err := t.fetch(op.ctx)
+	op.end(err)
+	return err
+}
+
+func (t *Texture) fetch(ctx context.Context) error {
+	resp, err := t.Mc.textureResponse(ctx, t.URL)
+	if resp != nil {
+		defer resp.Body.Close()
 	}
 	if err != nil {
 		return errors.Wrap(err, "unable to Fetch Texture")
 	}
 
-	err = t.Decode(apiBody)
+	body := io.Reader(resp.Body)
+	if t.Mc.MaxTextureBytes > 0 {
+		body = io.LimitReader(resp.Body, t.Mc.MaxTextureBytes+1)
+	}
+	raw, err := ioutil.ReadAll(body)
+	if err != nil {
+		return errors.Wrap(err, "unable to Fetch Texture")
+	}
+	if t.Mc.MaxTextureBytes > 0 && int64(len(raw)) > t.Mc.MaxTextureBytes {
+		return errors.Errorf("unable to Fetch Texture: larger than %d bytes", t.Mc.MaxTextureBytes)
+	}
+
+	op := t.Mc.startOperation(ctx, "minecraft.texture.decode", slog.Int("bytes", len(raw)))
+	err = t.Decode(bytes.NewReader(raw))
+	op.end(err)
 	if err != nil {
 		return errors.Wrap(err, "unable to Decode Texture")
 	}
+
+	t.ContentLength = resp.ContentLength
+	t.Header = resp.Header
+	t.FetchedAt = time.Now()
+	t.FinalURL = resp.Request.URL.String()
+	t.Stale = isStale(resp)
 	return nil
 }
 
+// WriteRaw writes out the texture exactly as it was served
+func (t *Texture) WriteRaw(w io.Writer) (int, error) {
+	if t.Raw == nil {
+		return 0, errors.New("unable to WriteRaw: texture has no raw bytes")
+	}
+
+	n, err := w.Write(t.Raw)
+	if err != nil {
+		return n, errors.Wrap(err, "unable to WriteRaw")
+	}
+	return n, nil
+}
+
 // FetchWithTextureProperty takes a already decoded Texture Property and will request either Skin or Cape as instructed
 func (t *Texture) FetchWithTextureProperty(profileTextureProperty SessionProfileTextureProperty, textureType string) error {
+	return t.fetchWithTextureProperty(context.Background(), profileTextureProperty, textureType)
+}
+
+func (t *Texture) fetchWithTextureProperty(ctx context.Context, profileTextureProperty SessionProfileTextureProperty, textureType string) error {
 	if textureType == "Skin" {
 		t.URL = profileTextureProperty.Textures.Skin.URL
 	} else if textureType == "Cape" {
@@ -104,30 +189,46 @@ func (t *Texture) FetchWithTextureProperty(profileTextureProperty SessionProfile
 	}
 	t.Source = "SessionProfile"
 
-	err := t.Fetch()
+	err := t.fetchContext(ctx)
 	if err != nil {
 		return errors.Wrap(err, "FetchWithTextureProperty failed")
 	}
+
+	if textureType == "Skin" {
+		t.Model = profileTextureProperty.Textures.Skin.Metadata.Model
+	}
+	if profileTextureProperty.TimestampMs != 0 {
+		t.Timestamp = profileTextureProperty.Time()
+	}
 	return nil
 }
 
 // FetchWithSessionProfile will decode the Texture Property for you and request the Skin or Cape as instructed
 // If requesting both Skin and Cape, this would result in 2 x decoding - use FetchWithTextureProperty instead
 func (t *Texture) FetchWithSessionProfile(sessionProfile SessionProfileResponse, textureType string) error {
+	return t.fetchWithSessionProfile(context.Background(), sessionProfile, textureType)
+}
+
+func (t *Texture) fetchWithSessionProfile(ctx context.Context, sessionProfile SessionProfileResponse, textureType string) error {
 	profileTextureProperty, err := DecodeTextureProperty(sessionProfile)
 	if err != nil {
 		return errors.WithStack(err)
 	}
 
-	err = t.FetchWithTextureProperty(profileTextureProperty, textureType)
+	err = t.fetchWithTextureProperty(ctx, profileTextureProperty, textureType)
 	if err != nil {
 		return errors.Wrap(err, "FetchWithSessionProfile failed")
 	}
+	t.Stale = t.Stale || sessionProfile.Stale
 	return nil
 }
 
 // FetchWithUsername takes a username and will then request from UsernameAPI as specified in the Minecraft struct
 func (t *Texture) FetchWithUsername(username string, textureType string) error {
+	return t.fetchWithUsername(context.Background(), username, textureType)
+}
+
+func (t *Texture) fetchWithUsername(ctx context.Context, username string, textureType string) error {
 	if textureType == "Skin" && t.Mc.UsernameAPI.SkinURL != "" {
 		t.URL = t.Mc.UsernameAPI.SkinURL + username + ".png"
 	} else if textureType == "Cape" && t.Mc.UsernameAPI.CapeURL != "" {
@@ -137,7 +238,7 @@ func (t *Texture) FetchWithUsername(username string, textureType string) error {
 	}
 	t.Source = "UsernameAPI"
 
-	err := t.Fetch()
+	err := t.fetchContext(ctx)
 	if err != nil {
 		return errors.Wrap(err, "FetchWithUsername failed")
 	}
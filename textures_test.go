@@ -2,7 +2,11 @@
 package minecraft
 
 import (
+	"bytes"
+	"image"
+	"image/color"
 	"testing"
+	"time"
 
 	"github.com/minotar/minecraft/mockminecraft"
 	. "github.com/smartystreets/goconvey/convey"
@@ -21,6 +25,33 @@ func TestTextures(t *testing.T) {
 			So(texture.Hash, ShouldEqual, "a04a26d10218668a632e419ab073cf57")
 		})
 
+		Convey("Fetched textures should keep the raw bytes and response details", func() {
+			texture := &Texture{Mc: mcTest, URL: "http://textures.minecraft.net/texture/cd9ca55e9862f003ebfa1872a9244ad5f721d6b9e6883dd1d42f87dae127649"}
+
+			err := texture.Fetch()
+			So(err, ShouldBeNil)
+			So(texture.Raw, ShouldNotBeEmpty)
+			So(texture.Header, ShouldNotBeNil)
+			So(texture.FetchedAt.IsZero(), ShouldBeFalse)
+			So(texture.FinalURL, ShouldEndWith, "/texture/cd9ca55e9862f003ebfa1872a9244ad5f721d6b9e6883dd1d42f87dae127649")
+
+			buf := &bytes.Buffer{}
+			n, err := texture.WriteRaw(buf)
+			So(err, ShouldBeNil)
+			So(n, ShouldEqual, len(texture.Raw))
+			So(buf.Bytes(), ShouldResemble, texture.Raw)
+
+			decoded := &Texture{}
+			So(decoded.Decode(buf), ShouldBeNil)
+			So(decoded.Hash, ShouldEqual, texture.Hash)
+		})
+
+		Convey("Textures without raw bytes cannot be written out", func() {
+			_, err := (&Texture{}).WriteRaw(&bytes.Buffer{})
+
+			So(err.Error(), ShouldEqual, "unable to WriteRaw: texture has no raw bytes")
+		})
+
 		Convey("Bad texture requests should gracefully fail", func() {
 
 			Convey("Bad texture URL (invalid-image)", func() {
@@ -54,6 +85,12 @@ func TestTextures(t *testing.T) {
 
 	})
 
+	Convey("Test SessionProfileTextureProperty.Time", t, func() {
+		property := SessionProfileTextureProperty{TimestampMs: 1500000000123}
+
+		So(property.Time().Equal(time.Unix(1500000000, 123000000)), ShouldBeTrue)
+	})
+
 	Convey("Test DecodeTextureProperty + Texture.FetchWithTextureProperty", t, func() {
 
 		Convey("Should correctly decode and fetch Skin and Cape URL", func() {
@@ -276,6 +313,44 @@ func TestTextures(t *testing.T) {
 
 	})
 
+	Convey("Test DefaultSkinModel", t, func() {
+
+		Convey("UUIDs with an odd hashCode should get Alex", func() {
+			for uuid, model := range map[string]string{
+				"10000000000000000000000000000000":     SkinModelClassic,
+				"10000000000000000000000000000001":     SkinModelSlim,
+				"00000001000000000000000000000000":     SkinModelSlim,
+				"00000001000000010000000000000000":     SkinModelClassic,
+				"00000000-0000-0000-0000-000100000000": SkinModelSlim,
+			} {
+				got, err := DefaultSkinModel(uuid)
+				So(err, ShouldBeNil)
+				So(got, ShouldEqual, model)
+			}
+		})
+
+		Convey("Usernames should gracefully fail", func() {
+			_, err := DefaultSkinModel("clone1018")
+
+			So(err.Error(), ShouldEqual, "unable to get DefaultSkinModel: \"clone1018\" is not a UUID")
+		})
+
+		Convey("The default skins should use the model", func() {
+			steve, err := mcTest.FetchDefaultSkin("10000000000000000000000000000000")
+			So(err, ShouldBeNil)
+			So(steve.Source, ShouldEqual, "Steve")
+			So(steve.Hash, ShouldEqual, SteveHash)
+
+			alex, err := mcTest.FetchDefaultSkin("10000000000000000000000000000001")
+			So(err, ShouldBeNil)
+			So(alex.Source, ShouldEqual, "Alex")
+			So(alex.URL, ShouldEqual, AlexURL)
+			So(alex.IsSlim(), ShouldBeTrue)
+			So(alex.Image.Bounds(), ShouldResemble, image.Rect(0, 0, 64, 64))
+		})
+
+	})
+
 	Convey("Test Skins", t, func() {
 
 		Convey("clone1018 should return valid image from Mojang", func() {
@@ -302,6 +377,29 @@ func TestTextures(t *testing.T) {
 			So(skin, ShouldResemble, Skin{Texture{Mc: mcTest}})
 		})
 
+		Convey("FetchSkinPlayer should take a Username or UUID", func() {
+			skin, err := mcTest.FetchSkinPlayer("clone1018")
+			So(err, ShouldBeNil)
+			So(skin.Source, ShouldEqual, "UsernameAPI")
+			So(skin.Hash, ShouldEqual, "a04a26d10218668a632e419ab073cf57")
+
+			skin, err = mcTest.FetchSkinPlayer("d9135e08-2f22-44c8-9cb0-bee234155292")
+			So(err, ShouldBeNil)
+			So(skin.Source, ShouldEqual, "SessionProfile")
+			So(skin.Hash, ShouldEqual, "a04a26d10218668a632e419ab073cf57")
+
+			// Without a UsernameAPI, usernames are looked up
+			mc := *mcTest
+			mc.UsernameAPI = UsernameAPI{}
+			skin, err = mc.FetchSkinPlayer("clone1018")
+			So(err, ShouldBeNil)
+			So(skin.Source, ShouldEqual, "SessionProfile")
+			So(skin.Hash, ShouldEqual, "a04a26d10218668a632e419ab073cf57")
+
+			_, err = mc.FetchSkinPlayer("_-proscope-_")
+			So(err.Error(), ShouldEqual, "unable to FetchSkinPlayer: unable to NormalizePlayerForUUID due to invalid Username/UUID")
+		})
+
 	})
 
 	Convey("Test Capes", t, func() {
@@ -340,6 +438,19 @@ func TestTextures(t *testing.T) {
 			So(cape, ShouldResemble, Cape{Texture{Mc: mcTest}})
 		})
 
+		Convey("FetchCapePlayer should take a Username or UUID", func() {
+			cape, err := mcTest.FetchCapePlayer("citricsquid")
+			So(err, ShouldBeNil)
+			So(cape.Hash, ShouldEqual, "8cbf8786caba2f05383cf887be592ee6")
+
+			cape, err = mcTest.FetchCapePlayer("48a0a7e4d5594873a617dc189f76a8a1")
+			So(err, ShouldBeNil)
+			So(cape.Hash, ShouldEqual, "8cbf8786caba2f05383cf887be592ee6")
+
+			_, err = mcTest.FetchCapePlayer("lukegb")
+			So(err, ShouldNotBeNil)
+		})
+
 	})
 
 	// This could be a lot more DRY but shush
@@ -439,3 +550,103 @@ func TestTextures(t *testing.T) {
 	})
 
 }
+
+func TestTextureVerify(t *testing.T) {
+
+	Convey("Test TextureIDFromURL", t, func() {
+
+		Convey("Texture URLs should give their ID", func() {
+			id, err := TextureIDFromURL("http://textures.minecraft.net/texture/b58a318a3ab7a0776378a28bb29e4287a85448abc3981a79f401e2b7ddf23")
+
+			So(err, ShouldBeNil)
+			So(id, ShouldEqual, "b58a318a3ab7a0776378a28bb29e4287a85448abc3981a79f401e2b7ddf23")
+		})
+
+		Convey("Other URLs should gracefully fail", func() {
+			_, err := TextureIDFromURL("http://skins.example.net/skins/clone1018.png")
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to get TextureIDFromURL: \"clone1018.png\" is not a texture ID")
+		})
+
+	})
+
+	Convey("Test ComputeYggdrasilTextureID", t, func() {
+
+		Convey("The ID should match the authlib-injector hash", func() {
+			// SHA-256 of 00000002 00000001 ffff0000 00000000
+			img := image.NewNRGBA(image.Rect(0, 0, 2, 1))
+			img.SetNRGBA(0, 0, color.NRGBA{255, 0, 0, 255})
+			img.SetNRGBA(1, 0, color.NRGBA{9, 9, 9, 0})
+
+			So(ComputeYggdrasilTextureID(img), ShouldEqual, "a66972c23875715c192bc7c93872d38d00b2694d10b9c8ba52719cd9fdfcf1fd")
+		})
+
+		Convey("Mojang texture IDs should not be reproduced", func() {
+			ids := map[string]string{
+				"cd9ca55e9862f003ebfa1872a9244ad5f721d6b9e6883dd1d42f87dae127649":  "ebd07429bfa1ef5a22490d19ffd60a67ac71c69d0cc89e6900cc3e9d5266e97",
+				"e1c6c9b6de88f4188f9732909c76dfcd7b16a40a031ce1b4868e4d1f8898e4f":  "75012f9bb407ccd85d54e3f32ccf5edcb2101865c93ae55ef3934c4334e9ab07",
+				"c3af7fb821254664558f28361158ca73303c9a85e96e5251102958d7ed60c4a3": "a57be94be678d5bd1d1298d4d34e349a22583cf51038d2014002bea95a30eb12",
+			}
+			for mojangID, yggdrasilID := range ids {
+				texture := &Texture{Mc: mcTest, URL: "http://textures.minecraft.net/texture/" + mojangID}
+				So(texture.Fetch(), ShouldBeNil)
+
+				So(ComputeYggdrasilTextureID(texture.Image), ShouldEqual, yggdrasilID)
+				So(texture.Verify(TextureIDYggdrasil), ShouldHaveSameTypeAs, &TextureHashMismatchError{})
+				So(texture.Verify(TextureIDUnknown), ShouldEqual, ErrTextureNotVerifiable)
+			}
+		})
+
+	})
+
+	Convey("Test Texture.Verify", t, func() {
+		steve, _ := FetchSkinForSteve()
+		id := ComputeYggdrasilTextureID(steve.Image)
+
+		Convey("Textures should be hashed with SHA-256 too", func() {
+			So(steve.HashSHA256, ShouldHaveLength, 64)
+		})
+
+		Convey("The ID should not depend on the colour of transparent pixels", func() {
+			tweaked := steve.clone()
+			tweaked.Image.(*image.NRGBA).SetNRGBA(0, 0, color.NRGBA{12, 34, 56, 0})
+
+			So(ComputeYggdrasilTextureID(tweaked.Image), ShouldEqual, id)
+		})
+
+		Convey("A matching texture should verify", func() {
+			texture := &Texture{Image: steve.Image, URL: "http://skins.example.net/textures/" + id}
+
+			So(texture.Verify(TextureIDYggdrasil), ShouldBeNil)
+		})
+
+		Convey("A tampered texture should not verify", func() {
+			tweaked := steve.clone()
+			tweaked.Image.(*image.NRGBA).SetNRGBA(8, 8, color.NRGBA{255, 0, 0, 255})
+			texture := &Texture{Image: tweaked.Image, URL: "http://skins.example.net/textures/" + id}
+
+			err := texture.Verify(TextureIDYggdrasil)
+			So(err, ShouldNotBeNil)
+			mismatch, ok := err.(*TextureHashMismatchError)
+			So(ok, ShouldBeTrue)
+			So(mismatch.Expected, ShouldEqual, id)
+			So(mismatch.Actual, ShouldEqual, ComputeYggdrasilTextureID(tweaked.Image))
+		})
+
+		Convey("Textures should only be verified when the algorithm is known", func() {
+			texture := &Texture{Image: steve.Image, URL: "http://skins.example.net/textures/" + id}
+
+			So(texture.Verify(TextureIDUnknown), ShouldEqual, ErrTextureNotVerifiable)
+		})
+
+		Convey("Textures without an ID should gracefully fail", func() {
+			err := (&Texture{Image: steve.Image, URL: "http://skins.example.net/skins/clone1018.png"}).Verify(TextureIDYggdrasil)
+
+			So(err, ShouldNotBeNil)
+			So(err.Error(), ShouldEqual, "unable to Verify texture: unable to get TextureIDFromURL: \"clone1018.png\" is not a texture ID")
+		})
+
+	})
+
+}
@@ -0,0 +1,246 @@
+package minecraft
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"sort"
+)
+
+// Severity says how serious a Violation is
+type Severity int
+
+const (
+	// SeverityWarning is for oddities the game copes with (eg. data in unused areas)
+	SeverityWarning Severity = iota
+	// SeverityError is for problems the game rejects or has to correct
+	SeverityError
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// Violation is a single problem found when validating a texture
+type Violation struct {
+	// Check is the name of the failed check, eg. "dimensions"
+	Check    string
+	Severity Severity
+	// Regions are the areas of the texture involved, eg. "head.front"
+	Regions []string
+	Message string
+}
+
+// ValidationReport lists everything wrong with a texture
+type ValidationReport struct {
+	Violations []Violation
+}
+
+// Valid reports whether the texture has no errors (warnings are allowed)
+func (r ValidationReport) Valid() bool {
+	return len(r.Errors()) == 0
+}
+
+// Errors returns the violations with SeverityError
+func (r ValidationReport) Errors() []Violation {
+	return r.filter(SeverityError)
+}
+
+// Warnings returns the violations with SeverityWarning
+func (r ValidationReport) Warnings() []Violation {
+	return r.filter(SeverityWarning)
+}
+
+func (r ValidationReport) filter(severity Severity) []Violation {
+	var violations []Violation
+	for _, v := range r.Violations {
+		if v.Severity == severity {
+			violations = append(violations, v)
+		}
+	}
+	return violations
+}
+
+func (r *ValidationReport) add(check string, severity Severity, regions []string, format string, args ...interface{}) {
+	r.Violations = append(r.Violations, Violation{
+		Check:    check,
+		Severity: severity,
+		Regions:  regions,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Validate checks the skin is one the game will accept and draw as intended:
+// its dimensions, colour model, that the base layer is opaque (the game fills
+// in any transparency there) and that nothing is drawn in the unused areas.
+func (s *Skin) Validate() ValidationReport {
+	report := ValidationReport{}
+	if !validateImage(&s.Texture, &report) {
+		return report
+	}
+
+	unit, err := s.layoutUnit()
+	if err != nil {
+		report.add("dimensions", SeverityError, nil, "%s, expected 64x64 or 64x32", err)
+		return report
+	}
+	if unit > 1 {
+		bounds := s.Image.Bounds()
+		report.add("dimensions", SeverityWarning, nil, "%dx%d is a high resolution skin, which the game does not support", bounds.Dx(), bounds.Dy())
+	}
+
+	img := toNRGBA(s.Image)
+	var transparent []string
+	for _, box := range s.Boxes() {
+		if box.Layer != LayerBase {
+			continue
+		}
+		for _, face := range BoxFaces {
+			if hasAnyTransparency(img, box.Face(face)) {
+				transparent = append(transparent, box.FaceName(face))
+			}
+		}
+	}
+	if len(transparent) > 0 {
+		report.add("base-transparency", SeverityError, transparent, "the base layer must be opaque but has transparent pixels")
+	}
+
+	validateUnused(img, s.Boxes(), &report)
+	return report
+}
+
+// Validate checks the cape is one the game will accept: its dimensions,
+// colour model and that nothing is drawn in the unused areas.
+func (c *Cape) Validate() ValidationReport {
+	report := ValidationReport{}
+	if !validateImage(&c.Texture, &report) {
+		return report
+	}
+
+	boxes := []SkinBox{}
+	box, err := c.CapeBox()
+	if err != nil {
+		report.add("dimensions", SeverityError, nil, "%s, expected 64x32 or 22x17", err)
+		return report
+	}
+	boxes = append(boxes, box)
+	if elytra, err := c.ElytraBox(); err == nil {
+		boxes = append(boxes, elytra)
+	}
+
+	if unit, _ := c.layoutUnit(); unit > 1 {
+		bounds := c.Image.Bounds()
+		report.add("dimensions", SeverityWarning, nil, "%dx%d is a high resolution cape, which the game does not support", bounds.Dx(), bounds.Dy())
+	}
+
+	validateUnused(toNRGBA(c.Image), boxes, &report)
+	return report
+}
+
+// validateImage checks there is an image, with a colour model that can hold
+// transparency. It returns false when no further checks are possible.
+func validateImage(t *Texture, report *ValidationReport) bool {
+	if t.Image == nil {
+		report.add("image", SeverityError, nil, "there is no image")
+		return false
+	}
+
+	if !hasAlphaChannel(t) {
+		report.add("color-model", SeverityError, nil, "the image must have an alpha channel")
+	}
+	return true
+}
+
+// hasAlphaChannel reports whether the texture was encoded with a colour model
+// that can hold transparency. Decoded images are always converted to NRGBA,
+// so where there are raw bytes the colour model is read from those instead.
+func hasAlphaChannel(t *Texture) bool {
+	model := t.Image.ColorModel()
+	if config, format, err := image.DecodeConfig(bytes.NewReader(t.Raw)); err == nil {
+		// DecodeConfig reports truecolour PNGs as RGBA whether or not they
+		// have alpha, so check the colour type in the IHDR chunk
+		if format == "png" && t.Raw[pngColorTypeOffset] == pngColorTypeTruecolor {
+			return false
+		}
+		model = config.ColorModel
+	}
+
+	switch model {
+	case color.NRGBAModel, color.RGBAModel, color.NRGBA64Model, color.RGBA64Model:
+		return true
+	}
+	_, paletted := model.(color.Palette)
+	return paletted
+}
+
+const (
+	// pngColorTypeOffset is where the colour type is in a PNG: after the
+	// signature, the IHDR chunk's length and type, the width, height and
+	// bit depth
+	pngColorTypeOffset = 8 + 4 + 4 + 4 + 4 + 1
+	// pngColorTypeTruecolor is RGB without an alpha channel
+	pngColorTypeTruecolor = 2
+)
+
+// validateUnused warns about any visible pixels outside the faces of the boxes
+func validateUnused(img *image.NRGBA, boxes []SkinBox, report *ValidationReport) {
+	bounds := img.Bounds()
+	used := make([]bool, bounds.Dx()*bounds.Dy())
+	for _, box := range boxes {
+		if box.Mirror {
+			continue
+		}
+		for _, face := range BoxFaces {
+			rect := box.Face(face).Intersect(bounds)
+			for y := rect.Min.Y; y < rect.Max.Y; y++ {
+				for x := rect.Min.X; x < rect.Max.X; x++ {
+					used[(y-bounds.Min.Y)*bounds.Dx()+x-bounds.Min.X] = true
+				}
+			}
+		}
+	}
+
+	// Name the unused pixels after the box they sit beside, where there is one
+	found := make(map[string]bool)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if used[(y-bounds.Min.Y)*bounds.Dx()+x-bounds.Min.X] || img.NRGBAAt(x, y).A == 0 {
+				continue
+			}
+
+			region := "unused"
+			for _, box := range boxes {
+				if !box.Mirror && image.Pt(x, y).In(box.Area()) {
+					region = box.Name + ".unused"
+					break
+				}
+			}
+			found[region] = true
+		}
+	}
+
+	if len(found) > 0 {
+		var regions []string
+		for region := range found {
+			regions = append(regions, region)
+		}
+		sort.Strings(regions)
+		report.add("unused-data", SeverityWarning, regions, "there are visible pixels in areas the game never draws")
+	}
+}
+
+// hasAnyTransparency reports whether any pixel in the area is not fully opaque
+func hasAnyTransparency(img *image.NRGBA, rect image.Rectangle) bool {
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			if img.NRGBAAt(x, y).A != 0xff {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package minecraft
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RegexTextureID matches the hash at the end of a texture URL
+var RegexTextureID = regexp.MustCompile("^[0-9a-f]{1,64}$")
+
+// TextureIDAlgorithm is how a texture server derives the texture IDs in its
+// URLs from the images
+type TextureIDAlgorithm int
+
+const (
+	// TextureIDUnknown is for servers whose texture IDs can't be recomputed
+	// from the image, including Mojang's (and mirrors of it)
+	TextureIDUnknown TextureIDAlgorithm = iota
+	// TextureIDYggdrasil is ComputeYggdrasilTextureID, as used by
+	// authlib-injector texture servers
+	TextureIDYggdrasil
+)
+
+// ErrTextureNotVerifiable is returned by Texture.Verify when the texture IDs
+// can't be recomputed with the given TextureIDAlgorithm
+var ErrTextureNotVerifiable = errors.New("unable to Verify texture: texture ID can't be recomputed")
+
+// TextureHashMismatchError is returned by Texture.Verify when the image does
+// not match the ID in its URL (eg. a corrupt or tampered copy from a mirror)
+type TextureHashMismatchError struct {
+	URL      string
+	Expected string
+	Actual   string
+}
+
+func (e *TextureHashMismatchError) Error() string {
+	return fmt.Sprintf("texture hash mismatch for %s: expected %s, got %s", e.URL, e.Expected, e.Actual)
+}
+
+// TextureIDFromURL returns the texture ID (hash) from the end of a texture URL
+// such as http://textures.minecraft.net/texture/<id>
+func TextureIDFromURL(textureURL string) (string, error) {
+	u, err := url.Parse(textureURL)
+	if err != nil {
+		return "", errors.Wrap(err, "unable to get TextureIDFromURL")
+	}
+
+	id := path.Base(u.Path)
+	if !RegexTextureID.MatchString(id) {
+		return "", errors.Errorf("unable to get TextureIDFromURL: %q is not a texture ID", id)
+	}
+	return id, nil
+}
+
+// ComputeYggdrasilTextureID derives the texture ID from the image the way
+// authlib-injector (Yggdrasil) texture servers such as Ely.by and LittleSkin
+// do: the SHA-256 of the width and height followed by every pixel as ARGB,
+// column by column, with fully transparent pixels zeroed. Like the IDs in
+// texture URLs, it is hex with any leading zeros dropped.
+//
+// Mojang's texture IDs are not derived this way.
+func ComputeYggdrasilTextureID(img image.Image) string {
+	bounds := img.Bounds()
+	hasher := sha256.New()
+
+	buf := make([]byte, 8, 4096)
+	binary.BigEndian.PutUint32(buf[0:], uint32(bounds.Dx()))
+	binary.BigEndian.PutUint32(buf[4:], uint32(bounds.Dy()))
+
+	for x := bounds.Min.X; x < bounds.Max.X; x++ {
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A == 0 {
+				buf = append(buf, 0, 0, 0, 0)
+			} else {
+				buf = append(buf, c.A, c.R, c.G, c.B)
+			}
+
+			if len(buf) == cap(buf) {
+				hasher.Write(buf)
+				buf = buf[:0]
+			}
+		}
+	}
+	hasher.Write(buf)
+
+	id := strings.TrimLeft(fmt.Sprintf("%x", hasher.Sum(nil)), "0")
+	if id == "" {
+		return "0"
+	}
+	return id
+}
+
+// TextureID returns the texture ID from the URL of the texture
+func (t *Texture) TextureID() (string, error) {
+	return TextureIDFromURL(t.URL)
+}
+
+// Verify recomputes the texture ID with the algorithm the texture server uses
+// and checks it against the ID in the URL. A mismatch is returned as a
+// *TextureHashMismatchError. Only TextureIDYggdrasil can be verified; the
+// server can't be told from the URL (mirrors serve Mojang's textures under
+// their own hosts) so it has to be chosen by the caller, and any other
+// algorithm gives ErrTextureNotVerifiable.
+func (t *Texture) Verify(algorithm TextureIDAlgorithm) error {
+	if algorithm != TextureIDYggdrasil {
+		return ErrTextureNotVerifiable
+	}
+	if t.Image == nil {
+		return errors.New("unable to Verify texture: no image")
+	}
+
+	expected, err := t.TextureID()
+	if err != nil {
+		return errors.Wrap(err, "unable to Verify texture")
+	}
+
+	actual := ComputeYggdrasilTextureID(t.Image)
+	if strings.TrimLeft(expected, "0") != actual {
+		return &TextureHashMismatchError{URL: t.URL, Expected: expected, Actual: actual}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package minecraft
+
+import (
+	"context"
+	"crypto/tls"
+	"log/slog"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Tracer starts spans around profile lookups, session lookups, texture
+// fetches, decodes and the HTTP requests they make. It is shaped so it can be
+// adapted to eg. OpenTelemetry.
+type Tracer interface {
+	// Start starts a span, a child of any span in ctx, returning a context
+	// holding the new span
+	Start(ctx context.Context, name string) (context.Context, Span)
+}
+
+// Span is a timed step of a lookup
+type Span interface {
+	SetAttributes(attrs ...slog.Attr)
+	// AddEvent marks something happening now (eg. "dns_done")
+	AddEvent(name string, attrs ...slog.Attr)
+	// End finishes the span, with the error it failed with (if any)
+	End(err error)
+}
+
+// operation is a step of a lookup, traced with the Tracer and logged with the
+// Logger. Without either, it does nothing.
+type operation struct {
+	ctx    context.Context
+	name   string
+	logger *slog.Logger
+	span   Span
+	start  time.Time
+
+	mu    sync.Mutex
+	attrs []slog.Attr
+}
+
+// startOperation starts an operation, a child of any in ctx
+func (mc *Minecraft) startOperation(ctx context.Context, name string, attrs ...slog.Attr) *operation {
+	op := &operation{ctx: ctx, name: name}
+	if mc == nil || (mc.Logger == nil && mc.Tracer == nil) {
+		return op
+	}
+
+	op.logger = mc.Logger
+	op.start = time.Now()
+	if mc.Tracer != nil {
+		op.ctx, op.span = mc.Tracer.Start(ctx, name)
+	}
+	op.setAttributes(attrs...)
+	return op
+}
+
+func (op *operation) enabled() bool {
+	return op.logger != nil || op.span != nil
+}
+
+func (op *operation) setAttributes(attrs ...slog.Attr) {
+	if !op.enabled() {
+		return
+	}
+	op.mu.Lock()
+	op.attrs = append(op.attrs, attrs...)
+	op.mu.Unlock()
+	if op.span != nil {
+		op.span.SetAttributes(attrs...)
+	}
+}
+
+func (op *operation) event(name string, attrs ...slog.Attr) {
+	if op.span != nil {
+		op.span.AddEvent(name, attrs...)
+	}
+}
+
+// end finishes the span and logs the operation, at Debug level when it
+// succeeded or Warn when it failed
+func (op *operation) end(err error) {
+	if !op.enabled() {
+		return
+	}
+	if op.span != nil {
+		op.span.End(err)
+	}
+	if op.logger == nil {
+		return
+	}
+
+	op.mu.Lock()
+	attrs := append([]slog.Attr{slog.Duration("duration", time.Since(op.start))}, op.attrs...)
+	op.mu.Unlock()
+
+	level := slog.LevelDebug
+	if err != nil {
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	op.logger.LogAttrs(op.ctx, level, op.name, attrs...)
+}
+
+// endResponse ends the operation for an HTTP request
+func (op *operation) endResponse(resp *http.Response, err error) {
+	if resp != nil {
+		op.setAttributes(slog.Int("status", resp.StatusCode))
+	}
+	op.end(err)
+}
+
+// clientTrace records the phases of the HTTP request as events on the
+// operation, and their durations as attributes
+func (op *operation) clientTrace() *httptrace.ClientTrace {
+	var dnsStart, connectStart, tlsStart time.Time
+	phase := func(event, name string, start time.Time) {
+		op.event(event)
+		if !start.IsZero() {
+			op.setAttributes(slog.Duration(name, time.Since(start)))
+		}
+	}
+
+	// Connecting may be attempted to several addresses at once
+	var mu sync.Mutex
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			op.event("dns_start")
+			dnsStart = time.Now()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			phase("dns_done", "dns", dnsStart)
+		},
+		ConnectStart: func(network, addr string) {
+			op.event("connect_start", slog.String("addr", addr))
+			mu.Lock()
+			if connectStart.IsZero() {
+				connectStart = time.Now()
+			}
+			mu.Unlock()
+		},
+		ConnectDone: func(network, addr string, err error) {
+			if err == nil {
+				mu.Lock()
+				start := connectStart
+				mu.Unlock()
+				phase("connect_done", "connect", start)
+			}
+		},
+		TLSHandshakeStart: func() {
+			op.event("tls_start")
+			tlsStart = time.Now()
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			phase("tls_done", "tls", tlsStart)
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			op.setAttributes(slog.Bool("conn_reused", info.Reused))
+		},
+		GotFirstResponseByte: func() {
+			phase("first_byte", "first_byte", op.start)
+		},
+	}
+}